@@ -0,0 +1,209 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+// AXNode is a node of the accessibility tree, as assistive technology sees
+// the page.
+type AXNode struct {
+	Role        string
+	Name        string
+	Value       string
+	Description string
+	// States are the node's properties, e.g. "focusable", "checked",
+	// "checked=false", "level=2".
+	States   []string
+	Children []*AXNode
+}
+
+// A11yTreeOptions configures AccessibilityTree.
+type A11yTreeOptions struct {
+	Selector string // root the tree at this element (default: the document)
+	// InterestingOnly drops nodes that carry no meaning of their own
+	// (generic containers, unnamed structure), keeping their children.
+	InterestingOnly bool
+}
+
+// AccessibilityTree returns the tab's accessibility tree. Nodes Chrome
+// ignores are left out, their children taking their place, so the result
+// can have several roots.
+func (c *Controller) AccessibilityTree(ctx context.Context, opts A11yTreeOptions) ([]*AXNode, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var backendID cdp.BackendNodeID
+	if opts.Selector != "" {
+		var nodes []*cdp.Node
+		if err := chromedp.Run(tabCtx, chromedp.Nodes(opts.Selector, &nodes, chromedp.ByQuery, chromedp.AtLeast(0))); err != nil {
+			return nil, err
+		}
+		if len(nodes) == 0 {
+			return nil, fmt.Errorf("no element matches %q", opts.Selector)
+		}
+		backendID = nodes[0].BackendNodeID
+	}
+
+	var nodes []*accessibility.Node
+	if err := runOnTab(tabCtx, func(ctx context.Context) error {
+		var err error
+		nodes, err = accessibility.GetFullAXTree().Do(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	root := nodes[0].NodeID
+	if backendID != 0 {
+		root = ""
+		for _, n := range nodes {
+			if n.BackendDOMNodeID == backendID {
+				root = n.NodeID
+				break
+			}
+		}
+		if root == "" {
+			return nil, fmt.Errorf("%q is not in the accessibility tree (hidden?)", opts.Selector)
+		}
+	}
+	return buildAXTree(nodes, root, opts.InterestingOnly), nil
+}
+
+// buildAXTree turns CDP's flat node list into the tree under root.
+func buildAXTree(nodes []*accessibility.Node, root accessibility.NodeID, interestingOnly bool) []*AXNode {
+	byID := make(map[accessibility.NodeID]*accessibility.Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.NodeID] = n
+	}
+	var build func(id accessibility.NodeID) []*AXNode
+	build = func(id accessibility.NodeID) []*AXNode {
+		n := byID[id]
+		if n == nil {
+			return nil
+		}
+		var children []*AXNode
+		for _, child := range n.ChildIDs {
+			children = append(children, build(child)...)
+		}
+		role := axString(n.Role)
+		// Inline text boxes repeat their StaticText line by line.
+		if n.Ignored || role == "InlineTextBox" {
+			return children
+		}
+		node := &AXNode{
+			Role:        role,
+			Name:        axString(n.Name),
+			Value:       axString(n.Value),
+			Description: axString(n.Description),
+		}
+		focusable := false
+		for _, p := range n.Properties {
+			if s, ok := axState(p); ok {
+				node.States = append(node.States, s)
+				focusable = focusable || s == "focusable"
+			}
+		}
+		if interestingOnly {
+			if node.Name != "" && onlyText(children) {
+				children = nil // the name already says it
+			}
+			if !focusable && !interestingRoles[role] && (len(children) > 0 || node.Name == "" && node.Value == "") {
+				return children
+			}
+		}
+		node.Children = children
+		return []*AXNode{node}
+	}
+	return build(root)
+}
+
+// interestingRoles are kept by InterestingOnly even when unnamed or with
+// children: widgets, and the roles that give a page its outline.
+var interestingRoles = map[string]bool{
+	"RootWebArea": true, "WebArea": true,
+	"button": true, "checkbox": true, "combobox": true, "link": true, "listbox": true,
+	"menu": true, "menubar": true, "menuitem": true, "menuitemcheckbox": true, "menuitemradio": true,
+	"option": true, "radio": true, "radiogroup": true, "scrollbar": true, "searchbox": true,
+	"slider": true, "spinbutton": true, "switch": true, "tab": true, "tablist": true,
+	"textbox": true, "tree": true, "treeitem": true,
+	"banner": true, "complementary": true, "contentinfo": true, "form": true, "main": true,
+	"navigation": true, "region": true, "search": true,
+	"alert": true, "alertdialog": true, "dialog": true, "heading": true, "image": true, "img": true,
+	"table": true,
+}
+
+func onlyText(nodes []*AXNode) bool {
+	for _, n := range nodes {
+		if n.Role != "StaticText" || len(n.Children) > 0 {
+			return false
+		}
+	}
+	return len(nodes) > 0
+}
+
+// axState renders a property as a state, e.g. "required" or "level=2".
+// Most false booleans and references to other nodes are skipped.
+func axState(p *accessibility.Property) (string, bool) {
+	if p == nil || p.Value == nil {
+		return "", false
+	}
+	switch p.Value.Type {
+	case accessibility.ValueTypeIdref, accessibility.ValueTypeIdrefList,
+		accessibility.ValueTypeNode, accessibility.ValueTypeNodeList:
+		return "", false
+	}
+	name := string(p.Name)
+	v := axString(p.Value)
+	switch {
+	case v == "":
+		return "", false
+	case v == "false" && p.Value.Type != accessibility.ValueTypeTristate:
+		// An unchecked checkbox says so (checked=false); other false
+		// states are noise, except for collapsed disclosures.
+		if p.Name == accessibility.PropertyNameExpanded {
+			return "collapsed", true
+		}
+		return "", false
+	case v == "true":
+		return name, true
+	default:
+		return name + "=" + v, true
+	}
+}
+
+// axString is a value as text; CDP encodes it as any JSON value.
+func axString(v *accessibility.Value) string {
+	if v == nil || len(v.Value) == 0 {
+		return ""
+	}
+	var x any
+	if err := json.Unmarshal(v.Value, &x); err != nil {
+		return string(v.Value)
+	}
+	switch x := x.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case nil:
+		return ""
+	default:
+		return string(v.Value)
+	}
+}
@@ -0,0 +1,112 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+)
+
+func axValue(typ accessibility.ValueType, raw string) *accessibility.Value {
+	return &accessibility.Value{Type: typ, Value: []byte(raw)}
+}
+
+func axNode(id, role, name string, children ...accessibility.NodeID) *accessibility.Node {
+	n := &accessibility.Node{NodeID: accessibility.NodeID(id), Role: axValue(accessibility.ValueTypeRole, `"`+role+`"`), ChildIDs: children}
+	if name != "" {
+		n.Name = axValue(accessibility.ValueTypeComputedString, `"`+name+`"`)
+	}
+	return n
+}
+
+func axProp(name accessibility.PropertyName, typ accessibility.ValueType, raw string) *accessibility.Property {
+	return &accessibility.Property{Name: name, Value: axValue(typ, raw)}
+}
+
+func testAXNodes() []*accessibility.Node {
+	heading := axNode("3", "heading", "Hello", "4")
+	heading.Properties = []*accessibility.Property{axProp("level", accessibility.ValueTypeInteger, "1")}
+	box := axNode("7", "checkbox", "Subscribe")
+	box.Properties = []*accessibility.Property{
+		axProp("focusable", accessibility.ValueTypeBooleanOrUndefined, "true"),
+		axProp("checked", accessibility.ValueTypeTristate, `"false"`),
+		axProp("disabled", accessibility.ValueTypeBoolean, "false"),
+		axProp("labelledby", accessibility.ValueTypeNodeList, `[]`),
+	}
+	ignored := axNode("5", "none", "", "6")
+	ignored.Ignored = true
+	return []*accessibility.Node{
+		axNode("1", "RootWebArea", "Page", "2"),
+		axNode("2", "generic", "", "3", "5", "8"),
+		heading,
+		axNode("4", "StaticText", "Hello", "9"),
+		ignored,
+		axNode("6", "generic", "", "7"),
+		box,
+		axNode("8", "paragraph", "", "10"),
+		axNode("9", "InlineTextBox", "Hello"),
+		axNode("10", "StaticText", "Some text"),
+	}
+}
+
+func TestBuildAXTree(t *testing.T) {
+	got := buildAXTree(testAXNodes(), "1", false)
+	want := []*AXNode{{Role: "RootWebArea", Name: "Page", Children: []*AXNode{
+		{Role: "generic", Children: []*AXNode{
+			{Role: "heading", Name: "Hello", States: []string{"level=1"}, Children: []*AXNode{{Role: "StaticText", Name: "Hello"}}},
+			{Role: "generic", Children: []*AXNode{{Role: "checkbox", Name: "Subscribe", States: []string{"focusable", "checked=false"}}}},
+			{Role: "paragraph", Children: []*AXNode{{Role: "StaticText", Name: "Some text"}}},
+		}},
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tree mismatch:\n got %s\nwant %s", dumpAX(got), dumpAX(want))
+	}
+}
+
+func TestBuildAXTree_InterestingOnly(t *testing.T) {
+	got := buildAXTree(testAXNodes(), "1", true)
+	want := []*AXNode{{Role: "RootWebArea", Name: "Page", Children: []*AXNode{
+		{Role: "heading", Name: "Hello", States: []string{"level=1"}},
+		{Role: "checkbox", Name: "Subscribe", States: []string{"focusable", "checked=false"}},
+		{Role: "StaticText", Name: "Some text"},
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tree mismatch:\n got %s\nwant %s", dumpAX(got), dumpAX(want))
+	}
+
+	// An ignored root leaves its children.
+	if got := buildAXTree(testAXNodes(), "5", true); len(got) != 1 || got[0].Role != "checkbox" {
+		t.Fatalf("subtree = %s", dumpAX(got))
+	}
+}
+
+func TestAXState(t *testing.T) {
+	for _, tc := range []struct {
+		prop *accessibility.Property
+		want string
+	}{
+		{axProp("required", accessibility.ValueTypeBoolean, "true"), "required"},
+		{axProp("expanded", accessibility.ValueTypeBooleanOrUndefined, "false"), "collapsed"},
+		{axProp("pressed", accessibility.ValueTypeTristate, `"mixed"`), "pressed=mixed"},
+		{axProp("valuenow", accessibility.ValueTypeNumber, "0.5"), "valuenow=0.5"},
+		{axProp("invalid", accessibility.ValueTypeToken, `"false"`), ""},
+		{axProp("controls", accessibility.ValueTypeIdrefList, `[]`), ""},
+	} {
+		got, ok := axState(tc.prop)
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("axState(%s) = %q, %v; want %q", tc.prop.Name, got, ok, tc.want)
+		}
+	}
+}
+
+func dumpAX(nodes []*AXNode) string {
+	var s string
+	for _, n := range nodes {
+		s += "(" + n.Role + " " + n.Name
+		for _, st := range n.States {
+			s += " " + st
+		}
+		s += dumpAX(n.Children) + ")"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+// parseAttach accepts either a DevTools port ("9222") or a websocket URL
+// ("ws://127.0.0.1:9222/devtools/browser/<id>").
+func parseAttach(spec string) (wsURL string, port int, err error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", 0, errors.New("missing attach target")
+	}
+	if p, err := strconv.Atoi(spec); err == nil {
+		if p <= 0 || p > 65535 {
+			return "", 0, fmt.Errorf("invalid DevTools port %d", p)
+		}
+		return "", p, nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return "", 0, err
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return "", 0, fmt.Errorf("attach target must be a DevTools port or ws:// URL, got %q", spec)
+	}
+	if ps := u.Port(); ps != "" {
+		port, _ = strconv.Atoi(ps)
+	}
+	return spec, port, nil
+}
+
+// attachLocked connects to an existing browser per c.opts.Attach, then opens a
+// new tab (or adopts the first page tab). Callers must hold c.mu.
+func (c *Controller) attachLocked(ctx context.Context) error {
+	wsURL, port, err := parseAttach(c.opts.Attach)
+	if err != nil {
+		return err
+	}
+	if wsURL == "" {
+		if wsURL, err = DevToolsWebSocketURL(port); err != nil {
+			return fmt.Errorf("attach to DevTools port %d: %w", port, err)
+		}
+	}
+
+	var targetID target.ID
+	if c.opts.AdoptTab && port != 0 {
+		if targets, err := DevToolsTargets(port); err == nil {
+			targetID = pickTarget(targets, "")
+		}
+	}
+
+	// Without a target ID chromedp opens a new tab on the remote browser.
+	tabCtx, cancel, err := newRemoteTabContext(ctx, wsURL, targetID)
+	if err != nil {
+		return fmt.Errorf("attach %s: %w", wsURL, err)
+	}
+
+	c.proc = nil
+	c.browserPID = 0
+	c.adopted = targetID != ""
+	c.connectedLocked(tabCtx, cancel, wsURL, port)
+	return nil
+}
+
+// releaseAdoptedLocked detaches from an adopted tab so that tearing down the
+// chromedp context leaves it open in the user's browser. Callers must hold c.mu.
+func (c *Controller) releaseAdoptedLocked() {
+	if !c.adopted || c.conn == nil {
+		return
+	}
+	cc := chromedp.FromContext(c.conn)
+	if cc == nil || cc.Target == nil || cc.Browser == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = target.DetachFromTarget().WithSessionID(cc.Target.SessionID).Do(cdp.WithExecutor(ctx, cc.Browser))
+	// chromedp closes the context's target on cancel unless it has none.
+	cc.Target = nil
+	c.adopted = false
+}
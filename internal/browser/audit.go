@@ -0,0 +1,274 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// A11ySeverities are the severities of audit violations, most severe first.
+var A11ySeverities = []string{"critical", "serious", "moderate", "minor"}
+
+// A11yViolation is a failed audit rule on one element.
+type A11yViolation struct {
+	Rule     string
+	Severity string // one of A11ySeverities
+	Selector string // CSS selector of the element; empty if it couldn't be located
+	Message  string
+	HTML     string // the element's opening tag
+}
+
+// a11yHelpersJS defines cssPath (a selector unique enough to find el again)
+// and openTag (el's opening tag, shortened).
+const a11yHelpersJS = `
+const cssPath = el => {
+  const parts = [];
+  for (; el && el.nodeType === 1; el = el.parentElement) {
+    if (el.id && document.querySelectorAll('#' + CSS.escape(el.id)).length === 1) {
+      parts.unshift('#' + CSS.escape(el.id));
+      break;
+    }
+    let part = el.localName;
+    const same = el.parentElement ? [...el.parentElement.children].filter(c => c.localName === el.localName) : [];
+    if (same.length > 1) part += ':nth-of-type(' + (same.indexOf(el) + 1) + ')';
+    parts.unshift(part);
+  }
+  return parts.join(' > ');
+};
+const openTag = el => {
+  const h = el.outerHTML.replace(/\s+/g, ' ');
+  const tag = h.slice(0, h.indexOf('>') + 1);
+  return tag.length > 160 ? tag.slice(0, 157) + '...' : tag;
+};
+`
+
+// describeNodeJS runs on a node (Runtime.callFunctionOn) and locates it.
+// scroller marks elements Chrome only makes focusable so that keyboard users
+// can scroll them.
+const describeNodeJS = `function() {` + a11yHelpersJS + `
+  const el = this.nodeType === 1 ? this : this.parentElement;
+  if (!el) return {selector: '', html: '', scroller: false};
+  const s = getComputedStyle(el);
+  const scroller = !el.hasAttribute('tabindex') && /auto|scroll/.test(s.overflowX + s.overflowY) &&
+    (el.scrollHeight > el.clientHeight || el.scrollWidth > el.clientWidth);
+  return {selector: cssPath(el), html: openTag(el), scroller};
+}`
+
+// auditScript checks the rules that need the DOM and computed styles.
+const auditScript = `(() => {` + a11yHelpersJS + `
+  const out = [];
+  const report = (rule, severity, el, message) =>
+    out.push({rule, severity, message, selector: cssPath(el), html: openTag(el)});
+  const visible = el => {
+    if (!el.getClientRects().length) return false;
+    const s = getComputedStyle(el);
+    return s.visibility !== 'hidden' && s.display !== 'none';
+  };
+
+  const lang = document.documentElement.getAttribute('lang');
+  if (!lang || !lang.trim()) report('html-lang', 'serious', document.documentElement, 'The page has no lang attribute');
+
+  for (const el of document.querySelectorAll('img, input[type=image]')) {
+    if (!visible(el) || el.hasAttribute('alt') || el.getAttribute('aria-label') || el.getAttribute('aria-labelledby') || el.getAttribute('title')) continue;
+    if (['presentation', 'none'].includes(el.getAttribute('role'))) continue;
+    report('image-alt', 'critical', el, el.localName === 'img' ? 'Image has no alt text' : 'Image button has no alt text');
+  }
+
+  const ids = new Map();
+  for (const el of document.querySelectorAll('[id]')) {
+    if (el.id) ids.set(el.id, (ids.get(el.id) || []).concat(el));
+  }
+  for (const [id, els] of ids) {
+    if (els.length < 2) continue;
+    const q = CSS.escape(id);
+    const referenced = document.querySelector('label[for="' + q + '"], [aria-labelledby~="' + q + '"], [aria-describedby~="' + q + '"], [aria-controls~="' + q + '"]');
+    report('duplicate-id', referenced ? 'serious' : 'minor', els[1],
+      'id "' + id + '" is used ' + els.length + ' times' + (referenced ? ' and referenced by ARIA or a label' : ''));
+  }
+
+  let prev = 0;
+  for (const el of document.querySelectorAll('h1, h2, h3, h4, h5, h6, [role=heading]')) {
+    if (!visible(el)) continue;
+    const level = parseInt(el.getAttribute('aria-level') || el.localName.slice(1), 10) || 2;
+    if (prev && level > prev + 1) report('heading-order', 'moderate', el, 'Heading level ' + level + ' follows level ' + prev);
+    prev = level;
+  }
+
+  const parse = c => {
+    const m = /^rgba?\(([^)]+)\)$/.exec(c);
+    if (!m) return null;
+    const p = m[1].split(/[\s,\/]+/).filter(Boolean).map(parseFloat);
+    return {r: p[0], g: p[1], b: p[2], a: p.length > 3 ? p[3] : 1};
+  };
+  const over = (top, bottom) => ({
+    r: top.r * top.a + bottom.r * (1 - top.a),
+    g: top.g * top.a + bottom.g * (1 - top.a),
+    b: top.b * top.a + bottom.b * (1 - top.a),
+    a: 1,
+  });
+  // background composites the backgrounds behind el onto white; null if an
+  // image or an unparsable color is in the way.
+  const background = el => {
+    const layers = [];
+    for (; el; el = el.parentElement) {
+      const s = getComputedStyle(el);
+      if (s.backgroundImage !== 'none') return null;
+      const c = parse(s.backgroundColor);
+      if (!c) return null;
+      if (c.a > 0) layers.push(c);
+      if (c.a >= 1) break;
+    }
+    return layers.reverse().reduce((bg, c) => over(c, bg), {r: 255, g: 255, b: 255, a: 1});
+  };
+  const luminance = c => {
+    const f = v => (v /= 255) <= 0.03928 ? v / 12.92 : ((v + 0.055) / 1.055) ** 2.4;
+    return 0.2126 * f(c.r) + 0.7152 * f(c.g) + 0.0722 * f(c.b);
+  };
+  const hex = c => '#' + [c.r, c.g, c.b].map(v => Math.round(v).toString(16).padStart(2, '0')).join('');
+
+  const seen = new Set();
+  const walker = document.createTreeWalker(document.body || document.documentElement, NodeFilter.SHOW_TEXT);
+  for (let n = walker.nextNode(); n; n = walker.nextNode()) {
+    const el = n.parentElement;
+    if (!el || seen.has(el) || !n.data.trim()) continue;
+    seen.add(el);
+    if (['script', 'style', 'noscript', 'title'].includes(el.localName) || el.closest(':disabled') || !visible(el)) continue;
+    const s = getComputedStyle(el);
+    const color = parse(s.color);
+    const bg = background(el);
+    if (!color || !bg || color.a === 0 || parseFloat(s.opacity) === 0) continue;
+    const fg = over(color, bg);
+    const l1 = luminance(fg), l2 = luminance(bg);
+    const ratio = (Math.max(l1, l2) + 0.05) / (Math.min(l1, l2) + 0.05);
+    const size = parseFloat(s.fontSize), weight = parseInt(s.fontWeight, 10) || 400;
+    const needed = size >= 24 || (size >= 18.66 && weight >= 700) ? 3 : 4.5;
+    if (ratio < needed) {
+      report('color-contrast', 'serious', el,
+        'Contrast ' + ratio.toFixed(2) + ':1 (' + hex(fg) + ' on ' + hex(bg) + '), needs ' + needed + ':1');
+    }
+  }
+  return out;
+})()`
+
+// formControlRoles are the roles the label rule checks.
+var formControlRoles = []string{"textbox", "searchbox", "combobox", "listbox", "checkbox", "radio", "slider", "spinbutton", "switch"}
+
+// axViolation is a violation found in the AX tree, before its DOM node is
+// located.
+type axViolation struct {
+	backendID cdp.BackendNodeID
+	rule      string
+	severity  string
+	message   string
+}
+
+// axViolations checks the rules that need accessible names.
+func axViolations(nodes []*accessibility.Node) []axViolation {
+	var out []axViolation
+	for _, n := range nodes {
+		if n.Ignored || n.BackendDOMNodeID == 0 || axString(n.Name) != "" {
+			continue
+		}
+		role := axString(n.Role)
+		switch {
+		case slices.Contains(formControlRoles, role):
+			out = append(out, axViolation{n.BackendDOMNodeID, "label", "critical", fmt.Sprintf("Form control (%s) has no label", role)})
+		case role != "RootWebArea" && role != "WebArea" && slices.ContainsFunc(n.Properties, func(p *accessibility.Property) bool {
+			return p.Name == accessibility.PropertyNameFocusable && axString(p.Value) == "true"
+		}):
+			out = append(out, axViolation{n.BackendDOMNodeID, "focusable-name", "serious", fmt.Sprintf("Focusable %s has no accessible name", role)})
+		}
+	}
+	return out
+}
+
+// A11yAudit checks the tab for missing alt text (image-alt), unlabeled form
+// controls (label), low text contrast (color-contrast), skipped heading
+// levels (heading-order), duplicate IDs (duplicate-id), a missing page
+// language (html-lang) and unnamed focusable elements (focusable-name), using
+// the DOM, computed styles and the accessibility tree. Violations are sorted
+// by severity, then by document order within a rule.
+func (c *Controller) A11yAudit(ctx context.Context) ([]A11yViolation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []A11yViolation
+	var page []struct {
+		Rule     string `json:"rule"`
+		Severity string `json:"severity"`
+		Selector string `json:"selector"`
+		Message  string `json:"message"`
+		HTML     string `json:"html"`
+	}
+	if err := chromedp.Run(tabCtx, chromedp.Evaluate(auditScript, &page)); err != nil {
+		return nil, err
+	}
+	for _, v := range page {
+		found = append(found, A11yViolation(v))
+	}
+
+	err = runOnTab(tabCtx, func(ctx context.Context) error {
+		nodes, err := accessibility.GetFullAXTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, v := range axViolations(nodes) {
+			// The node can go away while the audit runs; report it anyway,
+			// without a selector.
+			d, err := describeNode(ctx, v.backendID)
+			if err != nil && ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if v.rule == "focusable-name" && d.Scroller {
+				continue
+			}
+			found = append(found, A11yViolation{Rule: v.rule, Severity: v.severity, Selector: d.Selector, Message: v.message, HTML: d.HTML})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(found, func(a, b A11yViolation) int {
+		return slices.Index(A11ySeverities, a.Severity) - slices.Index(A11ySeverities, b.Severity)
+	})
+	return found, nil
+}
+
+// nodeDescription is the result of describeNodeJS.
+type nodeDescription struct {
+	Selector string `json:"selector"`
+	HTML     string `json:"html"`
+	Scroller bool   `json:"scroller"`
+}
+
+// describeNode locates a DOM node for a report.
+func describeNode(ctx context.Context, id cdp.BackendNodeID) (nodeDescription, error) {
+	var out nodeDescription
+	obj, err := dom.ResolveNode().WithBackendNodeID(id).Do(ctx)
+	if err != nil {
+		return out, err
+	}
+	defer func() { _ = runtime.ReleaseObject(obj.ObjectID).Do(ctx) }()
+	res, exc, err := runtime.CallFunctionOn(describeNodeJS).WithObjectID(obj.ObjectID).WithReturnByValue(true).Do(ctx)
+	if err != nil {
+		return out, err
+	}
+	if exc != nil {
+		return out, exc
+	}
+	err = json.Unmarshal(res.Value, &out)
+	return out, err
+}
@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+)
+
+func TestAXViolations(t *testing.T) {
+	focusable := axProp(accessibility.PropertyNameFocusable, accessibility.ValueTypeBooleanOrUndefined, "true")
+	withDOM := func(n *accessibility.Node, id cdp.BackendNodeID, props ...*accessibility.Property) *accessibility.Node {
+		n.BackendDOMNodeID = id
+		n.Properties = props
+		return n
+	}
+	hidden := withDOM(axNode("6", "textbox", ""), 6)
+	hidden.Ignored = true
+	nodes := []*accessibility.Node{
+		withDOM(axNode("1", "RootWebArea", "", "2"), 1, focusable),
+		withDOM(axNode("2", "textbox", ""), 2, focusable),
+		withDOM(axNode("3", "textbox", "Email"), 3, focusable),
+		withDOM(axNode("4", "link", ""), 4, focusable),
+		withDOM(axNode("5", "link", "Home"), 5, focusable),
+		hidden,
+		withDOM(axNode("7", "generic", ""), 7),
+		axNode("8", "checkbox", ""), // no DOM node
+	}
+	want := []axViolation{
+		{2, "label", "critical", "Form control (textbox) has no label"},
+		{4, "focusable-name", "serious", "Focusable link has no accessible name"},
+	}
+	if got := axViolations(nodes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("axViolations = %+v, want %+v", got, want)
+	}
+}
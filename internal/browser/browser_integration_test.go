@@ -0,0 +1,453 @@
+package browser
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// newTestController launches a headless browser for integration tests. They
+// only run with CANVAS_BROWSER_TESTS=1 and a discoverable Chromium.
+func newTestController(t *testing.T, startURL string) *Controller {
+	t.Helper()
+	if os.Getenv("CANVAS_BROWSER_TESTS") != "1" {
+		t.Skip("set CANVAS_BROWSER_TESTS=1 to run browser integration tests")
+	}
+	bin, err := FindChromiumBinary()
+	if err != nil {
+		t.Skip(err)
+	}
+	// The browser process and its allocator live as long as ctx, so it must
+	// outlast the test; launch has its own startup timeout. Cleanups run last
+	// in first out: Close, then cancel.
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c, err := New(ctx, Options{
+		BrowserBin:  bin,
+		Headless:    true,
+		UserDataDir: t.TempDir(),
+		StartURL:    startURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestIntegration_NetworkThrottleSlowsLoads(t *testing.T) {
+	big := "<!doctype html><body><pre>" + strings.Repeat("canvas throttle test\n", 10_000) + "</pre></body>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(big))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	load := func(q string) time.Duration {
+		start := time.Now()
+		if _, _, err := c.Navigate(ctx, srv.URL+"/big?"+q); err != nil {
+			t.Fatal(err)
+		}
+		return time.Since(start)
+	}
+
+	baseline := load("a")
+	// ~210KB at 800kbit/s is ~2s on top of the added latency.
+	if err := c.SetNetworkConditions(ctx, &NetworkConditions{LatencyMs: 300, DownloadKbps: 800, UploadKbps: 800}); err != nil {
+		t.Fatal(err)
+	}
+	throttled := load("b")
+	if throttled-baseline < time.Second {
+		t.Fatalf("throttled load %v not measurably slower than baseline %v", throttled, baseline)
+	}
+
+	if err := c.SetNetworkConditions(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if again := load("c"); again > throttled/2 {
+		t.Fatalf("load after --off took %v (throttled %v)", again, throttled)
+	}
+}
+
+func TestIntegration_PerfReport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<!doctype html><body><h1>canvas perf</h1><p>" + strings.Repeat("text ", 200) + "</p></body>"))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rep, err := c.Perf(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Metrics["Nodes"] <= 0 || rep.Metrics["JSHeapUsedSize"] <= 0 {
+		t.Fatalf("metrics = %v", rep.Metrics)
+	}
+	if rep.TTFB < 0 || rep.Load <= 0 || rep.FCP <= 0 {
+		t.Fatalf("timing = %+v", rep)
+	}
+}
+
+func TestIntegration_Trace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<!doctype html><body><h1>canvas trace</h1></body>"))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if err := c.StartTrace(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartTrace(ctx, nil); err == nil {
+		t.Fatal("expected an error for a second trace")
+	}
+	if err := c.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	if err := c.StopTrace(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"traceEvents"`) {
+		t.Fatalf("trace doesn't look like trace event JSON: %.200s", buf.String())
+	}
+	if _, ok := c.Tracing(); ok {
+		t.Fatal("trace still marked as running")
+	}
+}
+
+func TestIntegration_ProfileAndHeapSnapshot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><body><script>
+function spin() { const end = performance.now() + 20; while (performance.now() < end) {} setTimeout(spin, 0); }
+spin();
+</script></body>`))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	p, err := c.CPUProfile(ctx, 500*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := SummarizeCPUProfile(p, 5)
+	if len(s.Top) == 0 || s.Top[0].Function != "spin" {
+		t.Fatalf("top = %+v", s.Top)
+	}
+
+	var buf strings.Builder
+	if err := c.HeapSnapshot(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), `{"snapshot":`) {
+		t.Fatalf("snapshot doesn't look like a heapsnapshot: %.100s", buf.String())
+	}
+}
+
+func TestIntegration_Coverage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><link rel="stylesheet" href="/app.css"><body><h1>canvas coverage</h1><script src="/app.js"></script></body>`))
+	})
+	mux.HandleFunc("/app.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		_, _ = w.Write([]byte("function used() { return 1; }\nfunction unused() { return 2; }\nused();\n"))
+	})
+	mux.HandleFunc("/app.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		_, _ = w.Write([]byte("h1 { color: red; }\n.missing { color: blue; }\n"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if err := c.StartCoverage(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := c.StopCoverage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]int{}
+	for _, s := range sources {
+		covered, uncovered := s.Lines()
+		switch s.URL {
+		case srv.URL + "/app.js", srv.URL + "/app.css":
+			got[s.Type+":covered"] = covered
+			got[s.Type+":uncovered"] = uncovered
+		}
+	}
+	for key, want := range map[string][]int{
+		"js:covered": {1, 3}, "js:uncovered": {2},
+		"css:covered": {1}, "css:uncovered": {2},
+	} {
+		if !slices.Equal(got[key], want) {
+			t.Errorf("%s = %v, want %v", key, got[key], want)
+		}
+	}
+	if _, ok := c.Covering(); ok {
+		t.Fatal("coverage still marked as running")
+	}
+}
+
+func TestIntegration_PDF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<!doctype html><title>canvas pdf</title><body><h1>canvas pdf</h1></body>"))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	buf, err := c.PDF(ctx, PDFOptions{PaperWidth: 8.27, PaperHeight: 11.69, FooterTemplate: `<span class="pageNumber"></span>`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(buf), "%PDF-") {
+		t.Fatalf("not a PDF: %.20q", buf)
+	}
+}
+
+func TestIntegration_Screenshot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><style>body{margin:0}</style><body>
+<div id="box" style="position:absolute;top:40px;left:40px;width:100px;height:50px;background:red"></div>
+<div style="height:3000px"></div></body>`))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	size := func(opts ScreenshotOptions) (int, int) {
+		t.Helper()
+		buf, err := c.Screenshot(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := cmp.Or(opts.Format, "png"); format != want {
+			t.Fatalf("format = %s, want %s", format, want)
+		}
+		return cfg.Width, cfg.Height
+	}
+
+	if _, h := size(ScreenshotOptions{FullPage: true}); h < 3000 {
+		t.Fatalf("full page height = %d", h)
+	}
+	if w, h := size(ScreenshotOptions{Selector: "#box", Padding: 10}); w != 120 || h != 70 {
+		t.Fatalf("selector size = %dx%d", w, h)
+	}
+	if w, h := size(ScreenshotOptions{Clip: &Rect{X: 0, Y: 0, Width: 200, Height: 100}, Scale: 0.5, Format: "jpeg", Quality: 50}); w != 100 || h != 50 {
+		t.Fatalf("clip size = %dx%d", w, h)
+	}
+}
+
+func TestIntegration_ScreenshotWidths(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><style>body{margin:0} div{height:800px} @media (min-width:700px){div{height:400px}}</style><body><div></div></body>`))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	innerWidth := func() int {
+		t.Helper()
+		var w int
+		c.mu.Lock()
+		tabCtx, err := c.tabCtxLocked(ctx)
+		c.mu.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := chromedp.Run(tabCtx, chromedp.Evaluate(`innerWidth`, &w)); err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+	before := innerWidth()
+
+	shots, err := c.ScreenshotWidths(ctx, []int64{375, 1024}, ScreenshotOptions{Format: "png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shots) != 2 {
+		t.Fatalf("shots = %d", len(shots))
+	}
+	for i, want := range [][2]int{{375, 800}, {1024, 400}} {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(shots[i].Data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Width != want[0] || cfg.Height < want[1] {
+			t.Errorf("width %d: %dx%d, want %d wide and at least %d high", shots[i].Width, cfg.Width, cfg.Height, want[0], want[1])
+		}
+	}
+	if after := innerWidth(); after != before {
+		t.Fatalf("viewport width after = %d, want %d", after, before)
+	}
+}
+
+func TestIntegration_AccessibilityTree(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><title>T</title><body><div><div>
+<h1>Hello</h1><form id="f"><label>Email <input required></label><button>Send</button></form>
+<p hidden>secret</p></div></div></body>`))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	nodes, err := c.AccessibilityTree(ctx, A11yTreeOptions{InterestingOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Role != "RootWebArea" {
+		t.Fatalf("roots = %s", dumpAX(nodes))
+	}
+	tree := dumpAX(nodes)
+	for _, want := range []string{"(heading Hello level=1)", "(textbox Email focusable", "(button Send focusable"} {
+		if !strings.Contains(tree, want) {
+			t.Errorf("tree lacks %s: %s", want, tree)
+		}
+	}
+	if strings.Contains(tree, "secret") || strings.Contains(tree, "generic") {
+		t.Errorf("tree has hidden or generic nodes: %s", tree)
+	}
+
+	form, err := c.AccessibilityTree(ctx, A11yTreeOptions{Selector: "#f"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(form) != 1 || !strings.Contains(dumpAX(form), "button Send") || strings.Contains(dumpAX(form), "heading") {
+		t.Fatalf("form subtree = %s", dumpAX(form))
+	}
+}
+
+func TestIntegration_A11yAudit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><html><body>
+<h1>Title</h1><h3>Skipped</h3>
+<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" width="10" height="10">
+<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="" width="10" height="10">
+<input id="q"><label>Name <input id="n"></label>
+<p style="color:#aaa;background:#fff">faint</p><p style="color:#000">dark</p>
+<a href="/x"><span></span></a>
+<div id="dup"></div><div id="dup"></div>
+</body></html>`))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	violations, err := c.A11yAudit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	for _, v := range violations {
+		got[v.Rule] = append(got[v.Rule], v.Selector)
+	}
+	want := map[string][]string{
+		"image-alt":      {"body > img:nth-of-type(1)"},
+		"label":          {"#q"},
+		"color-contrast": {"body > p:nth-of-type(1)"},
+		"heading-order":  {"body > h3"},
+		"duplicate-id":   {"body > div:nth-of-type(2)"},
+		"html-lang":      {"html"},
+		"focusable-name": {"body > a"},
+	}
+	for rule, sels := range want {
+		if !slices.Equal(got[rule], sels) {
+			t.Errorf("%s: %v, want %v", rule, got[rule], sels)
+		}
+	}
+	if violations[0].Severity != "critical" || violations[len(violations)-1].Severity != "minor" {
+		t.Errorf("not sorted by severity: %+v", violations)
+	}
+}
+
+func TestIntegration_Screencast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!doctype html><body><h1 id="n">0</h1><script>
+let n = 0; setInterval(() => { document.getElementById('n').textContent = ++n; }, 50);
+</script></body>`))
+	}))
+	defer srv.Close()
+
+	c := newTestController(t, srv.URL+"/")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if err := c.StartScreencast(ctx, ScreencastOptions{Quality: 60, MaxWidth: 400}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Second)
+	cast, err := c.StopScreencast(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cast.Frames) < 3 {
+		t.Fatalf("frames = %d", len(cast.Frames))
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(cast.Frames[0].Data))
+	if err != nil || format != "jpeg" || cfg.Width > 400 {
+		t.Fatalf("first frame: %s %+v %v", format, cfg, err)
+	}
+	if _, ok := c.Screencasting(); ok {
+		t.Fatal("screencast still marked as running")
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net"
-	"os/exec"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/chromedp/cdproto/emulation"
@@ -17,17 +17,47 @@ import (
 
 type Controller struct {
 	mu            sync.Mutex
-	tabCtx        context.Context
+	opts          Options
+	conn          context.Context // chromedp context owning the DevTools connection
+	tabs          []*tab
+	active        *tab
 	cancelAll     context.CancelFunc
-	browserCmd    *exec.Cmd
+	proc          *process
 	browserBin    string
 	headless      bool
 	browserPID    int
 	devToolsPort  int
 	devToolsWSURL string
+	emulation     Emulation
+	trace         *traceSession
+	coverage      *coverageSession
+	screencast    *screencastSession
+	// interceptor is read by request handlers without c.mu (see handlePaused).
+	interceptor atomic.Pointer[Interceptor]
+
+	// gen increments on every (re)launch so monitors of a previous browser
+	// instance don't report its shutdown as a crash.
+	gen           int
+	closed        bool
+	adopted       bool
+	crashes       chan string
+	onTargetCrash func()
+
+	// subMu guards subs separately from mu: events are delivered while a
+	// command holding mu waits on the same event dispatcher.
+	subMu  sync.Mutex
+	subs   map[int]func(tabID string, ev any)
+	nextID int
 }
 
 type Options struct {
+	// Attach connects to an already-running browser (DevTools websocket URL or
+	// port) instead of launching one. The browser is left running on Close.
+	Attach string
+	// AdoptTab reuses the attached browser's first page tab instead of
+	// opening a new one.
+	AdoptTab bool
+
 	BrowserBin   string
 	Headless     bool
 	UserDataDir  string
@@ -39,70 +69,197 @@ type Options struct {
 }
 
 func New(ctx context.Context, opts Options) (*Controller, error) {
-	bin := opts.BrowserBin
-	if bin == "" {
-		var err error
-		bin, err = FindChromiumBinary()
+	if opts.Attach != "" {
+		c := &Controller{opts: opts, crashes: make(chan string, 1)}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err := c.attachLocked(ctx); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	if opts.BrowserBin == "" {
+		bin, err := FindChromiumBinary()
 		if err != nil {
 			return nil, err
 		}
+		opts.BrowserBin = bin
 	}
 
-	devToolsPort := opts.DevToolsPort
-	if devToolsPort == 0 {
+	if opts.DevToolsPort == 0 {
 		p, err := pickFreeLocalPort()
 		if err != nil {
 			return nil, err
 		}
-		devToolsPort = p
+		opts.DevToolsPort = p
 	}
 
 	if opts.WindowSize == "" {
 		opts.WindowSize = "1280,720"
 	}
 
+	c := &Controller{
+		opts:       opts,
+		browserBin: opts.BrowserBin,
+		headless:   opts.Headless,
+		crashes:    make(chan string, 1),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.launchLocked(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// launchLocked starts a browser with c.opts, attaches to its initial tab and
+// starts a crash monitor for it. Callers must hold c.mu.
+func (c *Controller) launchLocked(ctx context.Context) error {
 	launched, err := launch(ctx, LaunchOptions{
-		BrowserBin:   bin,
-		Headless:     opts.Headless,
-		UserDataDir:  opts.UserDataDir,
-		DevToolsPort: devToolsPort,
-		StartURL:     opts.StartURL,
-		AppMode:      opts.AppMode,
-		WindowSize:   opts.WindowSize,
+		BrowserBin:   c.opts.BrowserBin,
+		Headless:     c.opts.Headless,
+		UserDataDir:  c.opts.UserDataDir,
+		DevToolsPort: c.opts.DevToolsPort,
+		StartURL:     c.opts.StartURL,
+		AppMode:      c.opts.AppMode,
+		WindowSize:   c.opts.WindowSize,
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	tabCtx, cancel, err := newRemoteTabContext(ctx, launched.DevToolsWS, launched.TargetID)
 	if err != nil {
-		_ = terminateProcess(launched.Cmd, 2*time.Second)
-		return nil, fmt.Errorf("chromedp attach failed: %w", err)
+		_ = launched.Proc.terminate(2 * time.Second)
+		return fmt.Errorf("chromedp attach failed: %w", err)
 	}
 
-	c := &Controller{
-		tabCtx:        tabCtx,
-		cancelAll:     cancel,
-		browserCmd:    launched.Cmd,
-		browserBin:    bin,
-		headless:      opts.Headless,
-		browserPID:    launched.Cmd.Process.Pid,
-		devToolsPort:  launched.DevToolsPort,
-		devToolsWSURL: launched.DevToolsWS,
+	c.proc = launched.Proc
+	c.browserPID = launched.Proc.Pid()
+	c.connectedLocked(tabCtx, cancel, launched.DevToolsWS, launched.DevToolsPort)
+	return nil
+}
+
+// connectedLocked installs a freshly attached tab context as the root tab and
+// starts the crash monitor. Callers must hold c.mu.
+func (c *Controller) connectedLocked(tabCtx context.Context, cancel context.CancelFunc, wsURL string, port int) {
+	c.gen++
+	c.conn = tabCtx
+	c.cancelAll = cancel
+	c.devToolsPort = port
+	c.devToolsWSURL = wsURL
+
+	targetCrashed := make(chan struct{})
+	var once sync.Once
+	c.onTargetCrash = func() { once.Do(func() { close(targetCrashed) }) }
+
+	var lost <-chan struct{}
+	if cc := chromedp.FromContext(tabCtx); cc != nil && cc.Browser != nil {
+		lost = cc.Browser.LostConnection
 	}
 
-	if opts.Stealth {
-		_ = c.applyStealth()
+	root := &tab{id: chromedp.FromContext(tabCtx).Target.TargetID, ctx: tabCtx}
+	c.tabs = []*tab{root}
+	c.active = root
+	c.initTabLocked(root)
+
+	go c.monitor(c.gen, c.proc, targetCrashed, lost)
+}
+
+// monitor waits for the browser process to exit, a page target to crash or
+// the DevTools websocket to drop, and reports it on c.crashes unless the
+// browser was shut down on purpose.
+func (c *Controller) monitor(gen int, proc *process, targetCrashed, lost <-chan struct{}) {
+	var reason string
+	select {
+	case <-proc.Done():
+		reason = proc.exitReason()
+	case <-targetCrashed:
+		reason = "page target crashed"
+	case <-lost:
+		reason = "devtools connection lost"
+		// A dying browser usually drops the websocket first; prefer the
+		// process exit reason if it follows shortly.
+		select {
+		case <-proc.Done():
+			reason = proc.exitReason()
+		case <-time.After(200 * time.Millisecond):
+		}
 	}
 
-	return c, nil
+	c.mu.Lock()
+	stale := c.closed || gen != c.gen
+	c.mu.Unlock()
+	if stale {
+		return
+	}
+
+	select {
+	case c.crashes <- reason:
+	default:
+	}
+}
+
+// Crashes delivers a reason each time the browser dies unexpectedly (process
+// exit, Inspector.targetCrashed or a dropped DevTools connection).
+func (c *Controller) Crashes() <-chan string { return c.crashes }
+
+// Relaunch tears down whatever is left of the current browser and launches a
+// new one with the same Options.
+func (c *Controller) Relaunch(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("controller closed")
+	}
+	c.gen++
+	c.releaseAdoptedLocked()
+	c.closeTabsLocked()
+	if c.cancelAll != nil {
+		c.cancelAll()
+		c.cancelAll = nil
+	}
+	if c.opts.Attach != "" {
+		return c.attachLocked(ctx)
+	}
+	_ = c.proc.terminate(2 * time.Second)
+	return c.launchLocked(ctx)
 }
 
 func (c *Controller) BrowserBinary() string { return c.browserBin }
 func (c *Controller) Headless() bool        { return c.headless }
-func (c *Controller) BrowserPID() int       { return c.browserPID }
-func (c *Controller) DevToolsPort() int     { return c.devToolsPort }
-func (c *Controller) DevToolsWSURL() string { return c.devToolsWSURL }
+func (c *Controller) Attached() bool        { return c.opts.Attach != "" }
+
+func (c *Controller) BrowserPID() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.browserPID
+}
+
+func (c *Controller) DevToolsPort() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.devToolsPort
+}
+
+func (c *Controller) DevToolsWSURL() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.devToolsWSURL
+}
+
+// LastURL is the most recent main-frame URL of the active tab.
+func (c *Controller) LastURL() string {
+	c.mu.Lock()
+	t := c.active
+	c.mu.Unlock()
+	if t == nil {
+		return ""
+	}
+	return t.URL()
+}
 
 func pickFreeLocalPort() (int, error) {
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
@@ -116,55 +273,69 @@ func pickFreeLocalPort() (int, error) {
 func (c *Controller) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.cancelAll == nil {
+	if c.closed {
 		return nil
 	}
-	c.cancelAll()
-	_ = terminateProcess(c.browserCmd, 2*time.Second)
-	c.cancelAll = nil
+	c.closed = true
+	c.releaseAdoptedLocked()
+	c.closeTabsLocked()
+	if c.cancelAll != nil {
+		c.cancelAll()
+		c.cancelAll = nil
+	}
+	_ = c.proc.terminate(2 * time.Second)
 	return nil
 }
 
 func (c *Controller) Alive(ctx context.Context) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return false
+	}
 	var title string
-	err := chromedp.Run(c.tabCtx, chromedp.Title(&title))
+	err = chromedp.Run(tabCtx, chromedp.Title(&title))
 	return err == nil
 }
 
 func (c *Controller) Navigate(ctx context.Context, url string) (string, string, error) {
+	r, err := c.Load(ctx, url)
+	return r.URL, r.Title, err
+}
+
+// Load navigates like Navigate and also reports the document status, failed
+// subresources and page errors raised during the load.
+func (c *Controller) Load(ctx context.Context, url string) (LoadReport, error) {
 	if url == "" {
-		return "", "", errors.New("missing url")
+		return LoadReport{}, errors.New("missing url")
 	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
-	var title string
-	var loc string
-	err := chromedp.Run(c.tabCtx,
-		chromedp.Navigate(url),
-		chromedp.WaitReady("body", chromedp.ByQuery),
-		chromedp.Location(&loc),
-		chromedp.Title(&title),
-	)
-	return loc, title, err
+	return c.runLoadLocked(ctx, chromedp.Navigate(url))
 }
 
 func (c *Controller) Reload(ctx context.Context) error {
+	_, err := c.ReloadReport(ctx)
+	return err
+}
+
+// ReloadReport reloads the tab and reports like Load.
+func (c *Controller) ReloadReport(ctx context.Context) (LoadReport, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return chromedp.Run(c.tabCtx,
-		chromedp.Reload(),
-		chromedp.WaitReady("body", chromedp.ByQuery),
-	)
+	return c.runLoadLocked(ctx, chromedp.Reload())
 }
 
 func (c *Controller) Eval(ctx context.Context, expr string) (any, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var out any
-	if err := chromedp.Run(c.tabCtx, chromedp.Evaluate(expr, &out)); err != nil {
+	if err := chromedp.Run(tabCtx, chromedp.Evaluate(expr, &out)); err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -173,8 +344,12 @@ func (c *Controller) Eval(ctx context.Context, expr string) (any, error) {
 func (c *Controller) OuterHTML(ctx context.Context, selector string) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return "", err
+	}
 	var out string
-	if err := chromedp.Run(c.tabCtx, chromedp.OuterHTML(selector, &out, chromedp.ByQuery)); err != nil {
+	if err := chromedp.Run(tabCtx, chromedp.OuterHTML(selector, &out, chromedp.ByQuery)); err != nil {
 		return "", err
 	}
 	return out, nil
@@ -183,34 +358,26 @@ func (c *Controller) OuterHTML(ctx context.Context, selector string) (string, er
 func (c *Controller) Text(ctx context.Context, selector string) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return "", err
+	}
 	var out string
-	if err := chromedp.Run(c.tabCtx, chromedp.Text(selector, &out, chromedp.ByQuery)); err != nil {
+	if err := chromedp.Run(tabCtx, chromedp.Text(selector, &out, chromedp.ByQuery)); err != nil {
 		return "", err
 	}
 	return out, nil
 }
 
-func (c *Controller) Screenshot(ctx context.Context, selector string) ([]byte, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	var buf []byte
-	var action chromedp.Action
-	if selector == "" {
-		action = chromedp.CaptureScreenshot(&buf)
-	} else {
-		action = chromedp.Screenshot(selector, &buf, chromedp.NodeVisible, chromedp.ByQuery)
-	}
-	if err := chromedp.Run(c.tabCtx, action); err != nil {
-		return nil, err
-	}
-	return buf, nil
-}
-
 func (c *Controller) Location(ctx context.Context) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return "", err
+	}
 	var loc string
-	if err := chromedp.Run(c.tabCtx, chromedp.Location(&loc)); err != nil {
+	if err := chromedp.Run(tabCtx, chromedp.Location(&loc)); err != nil {
 		return "", err
 	}
 	return loc, nil
@@ -219,21 +386,22 @@ func (c *Controller) Location(ctx context.Context) (string, error) {
 func (c *Controller) Title(ctx context.Context) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return "", err
+	}
 	var title string
-	if err := chromedp.Run(c.tabCtx, chromedp.Title(&title)); err != nil {
+	if err := chromedp.Run(tabCtx, chromedp.Title(&title)); err != nil {
 		return "", err
 	}
 	return title, nil
 }
 
-func (c *Controller) applyStealth() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
+func applyStealth(tabCtx context.Context) error {
 	// Best-effort only: different Chromium builds support different CDP features.
-	_ = emulation.SetAutomationOverride(false).Do(c.tabCtx)
-	_ = page.Enable().Do(c.tabCtx)
-	_, err := page.AddScriptToEvaluateOnNewDocument(stealthScript).Do(c.tabCtx)
+	_ = emulation.SetAutomationOverride(false).Do(tabCtx)
+	_ = page.Enable().Do(tabCtx)
+	_, err := page.AddScriptToEvaluateOnNewDocument(stealthScript).Do(tabCtx)
 	return err
 }
 
@@ -267,7 +435,11 @@ func (c *Controller) QueryAll(ctx context.Context, selector, mode string) ([]str
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	runCtx, cancel := context.WithTimeout(c.tabCtx, 15*time.Second)
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runCtx, cancel := context.WithTimeout(tabCtx, 15*time.Second)
 	defer cancel()
 
 	var out []string
@@ -291,7 +463,11 @@ func (c *Controller) Attr(ctx context.Context, selector, name string) (*string,
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	runCtx, cancel := context.WithTimeout(c.tabCtx, 15*time.Second)
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runCtx, cancel := context.WithTimeout(tabCtx, 15*time.Second)
 	defer cancel()
 
 	var out any
@@ -316,7 +492,11 @@ func (c *Controller) Click(ctx context.Context, selector string) error {
 	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	runCtx, cancel := context.WithTimeout(c.tabCtx, 15*time.Second)
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithTimeout(tabCtx, 15*time.Second)
 	defer cancel()
 	return chromedp.Run(runCtx, chromedp.Click(selector, chromedp.ByQuery))
 }
@@ -327,7 +507,11 @@ func (c *Controller) Type(ctx context.Context, selector, text string, clear bool
 	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	runCtx, cancel := context.WithTimeout(c.tabCtx, 15*time.Second)
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithTimeout(tabCtx, 15*time.Second)
 	defer cancel()
 
 	actions := []chromedp.Action{
@@ -370,7 +554,11 @@ func (c *Controller) Wait(ctx context.Context, selector, state string, timeout t
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	runCtx, cancel := context.WithTimeout(c.tabCtx, timeout)
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithTimeout(tabCtx, timeout)
 	defer cancel()
 	return chromedp.Run(runCtx, action)
 }
@@ -0,0 +1,338 @@
+package browser
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/debugger"
+	"github.com/chromedp/cdproto/profiler"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+// SourceCoverage is the coverage of one script or style sheet. Offsets count
+// UTF-16 code units, as CDP does.
+type SourceCoverage struct {
+	URL  string
+	Type string // "js" or "css"
+	Text string
+	// StartLine is the 0-based line of Text within the resource at URL
+	// (non-zero for inline <script> and <style> blocks).
+	StartLine int
+	Used      []Range // sorted, disjoint
+}
+
+// Range is the half-open range [Start, End).
+type Range struct {
+	Start, End int
+}
+
+// coverageSession is the running coverage (at most one per controller). The
+// script and style sheet maps are filled by the event subscription.
+type coverageSession struct {
+	tabID       string
+	unsubscribe func()
+
+	mu      sync.Mutex
+	scripts map[runtime.ScriptID]*debugger.EventScriptParsed
+	sheets  map[css.StyleSheetID]*css.StyleSheetHeader
+}
+
+// StartCoverage starts recording JavaScript block coverage and CSS rule usage
+// of the tab.
+func (c *Controller) StartCoverage(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.coverage != nil {
+		return fmt.Errorf("coverage of tab %s is already running", c.coverage.tabID)
+	}
+	t, err := c.tabLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := &coverageSession{
+		tabID:   string(t.id),
+		scripts: map[runtime.ScriptID]*debugger.EventScriptParsed{},
+		sheets:  map[css.StyleSheetID]*css.StyleSheetHeader{},
+	}
+	s.unsubscribe = c.Subscribe(func(id string, ev any) {
+		if id != s.tabID {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch e := ev.(type) {
+		case *debugger.EventScriptParsed:
+			s.scripts[e.ScriptID] = e
+		case *css.EventStyleSheetAdded:
+			s.sheets[e.Header.StyleSheetID] = e.Header
+		}
+	})
+	err = runOnTab(t.ctx, func(ctx context.Context) error {
+		if err := profiler.Enable().Do(ctx); err != nil {
+			return err
+		}
+		if _, err := profiler.StartPreciseCoverage().WithCallCount(false).WithDetailed(true).Do(ctx); err != nil {
+			return err
+		}
+		// Debugger.enable reports the scripts parsed so far; without
+		// skipping pauses a `debugger;` statement would hang the page.
+		if _, err := debugger.Enable().Do(ctx); err != nil {
+			return err
+		}
+		if err := debugger.SetSkipAllPauses(true).Do(ctx); err != nil {
+			return err
+		}
+		// chromedp already enabled CSS; re-enable it so the existing style
+		// sheets are reported again.
+		if err := css.Disable().Do(ctx); err != nil {
+			return err
+		}
+		if err := css.Enable().Do(ctx); err != nil {
+			return err
+		}
+		return css.StartRuleUsageTracking().Do(ctx)
+	})
+	if err != nil {
+		s.unsubscribe()
+		return err
+	}
+	c.coverage = s
+	return nil
+}
+
+// Covering reports the tab of the running coverage, if any.
+func (c *Controller) Covering() (tabID string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.coverage == nil {
+		return "", false
+	}
+	return c.coverage.tabID, true
+}
+
+// StopCoverage ends the running coverage and returns it per script and style
+// sheet. Sources without a http(s) URL (evaluated snippets, extensions) and
+// those the page has dropped since are left out.
+func (c *Controller) StopCoverage(ctx context.Context) ([]SourceCoverage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.coverage
+	if s == nil {
+		return nil, errors.New("no coverage running")
+	}
+	c.coverage = nil
+	defer s.unsubscribe()
+	t, err := c.findTabLocked(s.tabID)
+	if err != nil {
+		return nil, fmt.Errorf("covered tab is gone: %w", err)
+	}
+
+	var out []SourceCoverage
+	err = runOnTab(t.ctx, func(ctx context.Context) error {
+		defer func() { _ = debugger.Disable().Do(ctx) }()
+		scripts, _, err := profiler.TakePreciseCoverage().Do(ctx)
+		if err != nil {
+			return err
+		}
+		if err := profiler.StopPreciseCoverage().Do(ctx); err != nil {
+			return err
+		}
+		rules, err := css.StopRuleUsageTracking().Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		parsed := s.scripts
+		sheets := s.sheets
+		s.scripts, s.sheets = nil, nil
+		s.mu.Unlock()
+
+		for _, sc := range scripts {
+			if !coverableURL(sc.URL) {
+				continue
+			}
+			text, _, err := debugger.GetScriptSource(sc.ScriptID).Do(ctx)
+			if err != nil {
+				continue
+			}
+			cov := SourceCoverage{URL: sc.URL, Type: "js", Text: text}
+			if p := parsed[sc.ScriptID]; p != nil {
+				cov.StartLine = int(p.StartLine)
+			}
+			cov.Used = usedJSRanges(utf16Len(text), sc.Functions)
+			out = append(out, cov)
+		}
+
+		bySheet := map[css.StyleSheetID][]*css.RuleUsage{}
+		for _, r := range rules {
+			bySheet[r.StyleSheetID] = append(bySheet[r.StyleSheetID], r)
+		}
+		for id, h := range sheets {
+			if !coverableURL(h.SourceURL) {
+				continue
+			}
+			text, err := css.GetStyleSheetText(id).Do(ctx)
+			if err != nil {
+				continue
+			}
+			out = append(out, SourceCoverage{
+				URL:       h.SourceURL,
+				Type:      "css",
+				Text:      text,
+				StartLine: int(h.StartLine),
+				Used:      usedCSSRanges(bySheet[id]),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(out, func(a, b SourceCoverage) int {
+		return cmp.Or(cmp.Compare(a.URL, b.URL), cmp.Compare(a.Type, b.Type), cmp.Compare(a.StartLine, b.StartLine))
+	})
+	return out, nil
+}
+
+func coverableURL(u string) bool {
+	return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")
+}
+
+// usedJSRanges flattens V8 block coverage into the ranges that ran. Ranges
+// nest (a function, then its blocks), and the innermost count wins, so paint
+// outer ranges first.
+func usedJSRanges(n int, fns []*profiler.FunctionCoverage) []Range {
+	var ranges []*profiler.CoverageRange
+	for _, fn := range fns {
+		ranges = append(ranges, fn.Ranges...)
+	}
+	slices.SortStableFunc(ranges, func(a, b *profiler.CoverageRange) int {
+		return cmp.Or(cmp.Compare(a.StartOffset, b.StartOffset), cmp.Compare(b.EndOffset, a.EndOffset))
+	})
+	used := make([]bool, n)
+	for _, r := range ranges {
+		start, end := clampRange(int(r.StartOffset), int(r.EndOffset), n)
+		for i := start; i < end; i++ {
+			used[i] = r.Count > 0
+		}
+	}
+	return toRanges(used)
+}
+
+// usedCSSRanges returns the ranges of the rules that matched.
+func usedCSSRanges(rules []*css.RuleUsage) []Range {
+	var out []Range
+	for _, r := range rules {
+		if r.Used && r.EndOffset > r.StartOffset {
+			out = append(out, Range{int(r.StartOffset), int(r.EndOffset)})
+		}
+	}
+	slices.SortFunc(out, func(a, b Range) int { return cmp.Compare(a.Start, b.Start) })
+	// Merge overlapping and adjacent rules.
+	merged := out[:0]
+	for _, r := range out {
+		if k := len(merged) - 1; k >= 0 && r.Start <= merged[k].End {
+			merged[k].End = max(merged[k].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func clampRange(start, end, n int) (int, int) {
+	return min(max(start, 0), n), min(max(end, 0), n)
+}
+
+func toRanges(used []bool) []Range {
+	var out []Range
+	for i := 0; i < len(used); {
+		if !used[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(used) && used[j] {
+			j++
+		}
+		out = append(out, Range{i, j})
+		i = j
+	}
+	return out
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// Len is the size of the source in code units.
+func (s SourceCoverage) Len() int {
+	return utf16Len(s.Text)
+}
+
+// UsedLen is the number of code units in Used.
+func (s SourceCoverage) UsedLen() int {
+	n := 0
+	for _, r := range s.Used {
+		n += r.End - r.Start
+	}
+	return n
+}
+
+// Lines splits the source into 1-based lines of the resource at URL and
+// reports which of them ran. A line counts as covered if any of its code
+// did; blank lines are in neither list.
+func (s SourceCoverage) Lines() (covered, uncovered []int) {
+	line := s.StartLine + 1
+	code, hit := false, false
+	flush := func() {
+		switch {
+		case hit:
+			covered = append(covered, line)
+		case code:
+			uncovered = append(uncovered, line)
+		}
+		line++
+		code, hit = false, false
+	}
+
+	off, ri := 0, 0
+	for _, r := range s.Text {
+		if r == '\n' {
+			flush()
+		} else if r != ' ' && r != '\t' && r != '\r' {
+			code = true
+			for ri < len(s.Used) && s.Used[ri].End <= off {
+				ri++
+			}
+			if ri < len(s.Used) && s.Used[ri].Start <= off {
+				hit = true
+			}
+		}
+		if r >= 0x10000 {
+			off += 2
+		} else {
+			off++
+		}
+	}
+	if code {
+		flush()
+	}
+	return covered, uncovered
+}
@@ -0,0 +1,61 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/profiler"
+)
+
+func TestUsedJSRanges(t *testing.T) {
+	fns := []*profiler.FunctionCoverage{
+		// A nested function that never ran, inside a block that did.
+		{FunctionName: "unused", Ranges: []*profiler.CoverageRange{{StartOffset: 20, EndOffset: 30, Count: 0}}},
+		{FunctionName: "", Ranges: []*profiler.CoverageRange{
+			{StartOffset: 0, EndOffset: 50, Count: 1},
+			{StartOffset: 40, EndOffset: 45, Count: 0}, // branch not taken
+		}},
+		{FunctionName: "loop", Ranges: []*profiler.CoverageRange{{StartOffset: 22, EndOffset: 26, Count: 3}}},
+	}
+	got := usedJSRanges(48, fns)
+	want := []Range{{0, 20}, {22, 26}, {30, 40}, {45, 48}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestUsedCSSRanges(t *testing.T) {
+	got := usedCSSRanges([]*css.RuleUsage{
+		{StartOffset: 30, EndOffset: 40, Used: true},
+		{StartOffset: 0, EndOffset: 10, Used: true},
+		{StartOffset: 10, EndOffset: 20, Used: true},
+		{StartOffset: 20, EndOffset: 30, Used: false},
+		{StartOffset: 35, EndOffset: 38, Used: true},
+	})
+	want := []Range{{0, 20}, {30, 40}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestSourceCoverageLines(t *testing.T) {
+	// Inline script starting on line 5 of its page; "é" and the emoji take
+	// one and two UTF-16 code units.
+	text := "\n  a();\n\n  if (x) {\n    é😀();\n  }\n"
+	s := SourceCoverage{
+		Text:      text,
+		StartLine: 4,
+		Used:      []Range{{0, 18}, {29, 30}}, // the last is line 9's ";"
+	}
+	covered, uncovered := s.Lines()
+	if want := []int{6, 8, 9}; !reflect.DeepEqual(covered, want) {
+		t.Errorf("covered = %v, want %v", covered, want)
+	}
+	if want := []int{10}; !reflect.DeepEqual(uncovered, want) {
+		t.Errorf("uncovered = %v, want %v", uncovered, want)
+	}
+	if got := s.UsedLen(); got != 19 {
+		t.Errorf("UsedLen = %d, want 19", got)
+	}
+}
@@ -38,3 +38,35 @@ func TestDevToolsWebSocketURL(t *testing.T) {
 		t.Fatalf("ws url mismatch: %q", got)
 	}
 }
+
+func TestParseAttach(t *testing.T) {
+	cases := []struct {
+		in       string
+		wantWS   string
+		wantPort int
+		wantErr  bool
+	}{
+		{in: "9222", wantPort: 9222},
+		{in: " 9333 ", wantPort: 9333},
+		{in: "ws://127.0.0.1:9222/devtools/browser/abc", wantWS: "ws://127.0.0.1:9222/devtools/browser/abc", wantPort: 9222},
+		{in: "ws://localhost/devtools/browser/abc", wantWS: "ws://localhost/devtools/browser/abc"},
+		{in: "", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "http://127.0.0.1:9222", wantErr: true},
+	}
+	for _, tc := range cases {
+		ws, port, err := parseAttach(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("parseAttach(%q): expected error", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parseAttach(%q): %v", tc.in, err)
+		}
+		if ws != tc.wantWS || port != tc.wantPort {
+			t.Fatalf("parseAttach(%q) = %q, %d", tc.in, ws, port)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	cdpbrowser "github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// Viewport is a device metrics override applied via the Emulation domain.
+type Viewport struct {
+	Device            string // preset name, if any
+	Width             int64
+	Height            int64
+	DeviceScaleFactor float64
+	Mobile            bool
+	Touch             bool
+	UserAgent         string
+}
+
+// Emulation is the set of overrides the controller re-applies to every tab
+// (including new tabs and tabs of a relaunched browser).
+type Emulation struct {
+	Viewport    *Viewport
+	Media       *Media
+	Locale      string // BCP 47 tag, e.g. "de-DE"
+	Timezone    string // IANA ID, e.g. "Asia/Tokyo"
+	Geolocation *Geolocation
+	Network     *NetworkConditions
+}
+
+// Geolocation is a position override for navigator.geolocation.
+type Geolocation struct {
+	Latitude  float64
+	Longitude float64
+	Accuracy  float64 // meters
+}
+
+// Media is a CSS media type/feature override (Emulation.setEmulatedMedia).
+// Empty fields are left at the browser default.
+type Media struct {
+	Type          string // "screen" | "print"
+	ColorScheme   string // prefers-color-scheme
+	ReducedMotion string // prefers-reduced-motion
+	ForcedColors  string // forced-colors
+	Contrast      string // prefers-contrast
+}
+
+var mediaValues = map[string][]string{
+	"type":                   {"screen", "print"},
+	"prefers-color-scheme":   {"light", "dark", "no-preference"},
+	"prefers-reduced-motion": {"reduce", "no-preference"},
+	"forced-colors":          {"active", "none"},
+	"prefers-contrast":       {"more", "less", "no-preference", "custom"},
+}
+
+// Validate reports the first field with an unsupported value.
+func (m Media) Validate() error {
+	for _, f := range m.fields() {
+		if f.value != "" && !slices.Contains(mediaValues[f.name], f.value) {
+			return fmt.Errorf("invalid %s %q (want %s)", f.name, f.value, strings.Join(mediaValues[f.name], "|"))
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether no media override is set.
+func (m Media) IsZero() bool {
+	return m == Media{}
+}
+
+type mediaField struct{ name, value string }
+
+func (m Media) fields() []mediaField {
+	return []mediaField{
+		{"type", m.Type},
+		{"prefers-color-scheme", m.ColorScheme},
+		{"prefers-reduced-motion", m.ReducedMotion},
+		{"forced-colors", m.ForcedColors},
+		{"prefers-contrast", m.Contrast},
+	}
+}
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	iPadUA    = "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	androidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"
+	galaxyUA  = "Mozilla/5.0 (Linux; Android 14; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"
+)
+
+var devicePresets = []Viewport{
+	{Device: "iPhone SE", Width: 375, Height: 667, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPhoneUA},
+	{Device: "iPhone 14", Width: 390, Height: 844, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iPhoneUA},
+	{Device: "iPhone 15", Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iPhoneUA},
+	{Device: "iPhone 15 Pro", Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iPhoneUA},
+	{Device: "iPhone 15 Plus", Width: 430, Height: 932, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iPhoneUA},
+	{Device: "iPhone 15 Pro Max", Width: 430, Height: 932, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iPhoneUA},
+	{Device: "Pixel 7", Width: 412, Height: 915, DeviceScaleFactor: 2.625, Mobile: true, Touch: true, UserAgent: androidUA},
+	{Device: "Pixel 8", Width: 412, Height: 915, DeviceScaleFactor: 2.625, Mobile: true, Touch: true, UserAgent: androidUA},
+	{Device: "Galaxy S23", Width: 360, Height: 780, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: galaxyUA},
+	{Device: "iPad Mini", Width: 768, Height: 1024, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUA},
+	{Device: "iPad Air", Width: 820, Height: 1180, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUA},
+	{Device: "iPad Pro 11", Width: 834, Height: 1194, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUA},
+	{Device: "iPad Pro 12.9", Width: 1024, Height: 1366, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUA},
+	{Device: "Laptop", Width: 1366, Height: 768, DeviceScaleFactor: 1},
+	{Device: "MacBook Air 13", Width: 1440, Height: 900, DeviceScaleFactor: 2},
+	{Device: "Desktop 1080p", Width: 1920, Height: 1080, DeviceScaleFactor: 1},
+	{Device: "Desktop 1440p", Width: 2560, Height: 1440, DeviceScaleFactor: 1},
+}
+
+// Devices returns the built-in device presets.
+func Devices() []Viewport {
+	out := make([]Viewport, len(devicePresets))
+	copy(out, devicePresets)
+	return out
+}
+
+// LookupDevice finds a preset by name, ignoring case and spaces/dashes.
+func LookupDevice(name string) (Viewport, bool) {
+	key := normalizeDeviceName(name)
+	for _, d := range devicePresets {
+		if normalizeDeviceName(d.Device) == key {
+			return d, true
+		}
+	}
+	return Viewport{}, false
+}
+
+func normalizeDeviceName(s string) string {
+	s = strings.ToLower(s)
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s)
+}
+
+// Emulation returns the overrides currently applied to the controller's tabs.
+func (c *Controller) Emulation() Emulation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := c.emulation
+	if out.Viewport != nil {
+		v := *out.Viewport
+		out.Viewport = &v
+	}
+	if out.Media != nil {
+		m := *out.Media
+		out.Media = &m
+	}
+	if out.Geolocation != nil {
+		g := *out.Geolocation
+		out.Geolocation = &g
+	}
+	if out.Network != nil {
+		n := *out.Network
+		out.Network = &n
+	}
+	return out
+}
+
+// SetViewport overrides device metrics on every tab; nil clears the override.
+func (c *Controller) SetViewport(ctx context.Context, v *Viewport) error {
+	if v != nil && (v.Width <= 0 || v.Height <= 0) {
+		return errors.New("viewport width and height must be positive")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.emulation
+	next := c.emulation
+	next.Viewport = v
+	err := c.applyToTabsLocked(func(ctx context.Context) error {
+		if err := applyViewport(ctx, v, prev.Viewport != nil); err != nil {
+			return err
+		}
+		return applyUserAgent(ctx, next, prev.Viewport != nil)
+	}, func(ctx context.Context) error {
+		if err := applyViewport(ctx, prev.Viewport, true); err != nil {
+			return err
+		}
+		return applyUserAgent(ctx, prev, true)
+	})
+	if err != nil {
+		return err
+	}
+	c.emulation = next
+	return nil
+}
+
+// applyToTabsLocked runs apply on every tab. If a tab fails, undo runs on the
+// tabs done so far, the failed one included, to put back the previous
+// override. Callers must hold c.mu.
+func (c *Controller) applyToTabsLocked(apply, undo func(ctx context.Context) error) error {
+	for i, t := range c.tabs {
+		if err := runOnTab(t.ctx, apply); err != nil {
+			for _, done := range c.tabs[:i+1] {
+				_ = runOnTab(done.ctx, undo)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func applyViewport(tabCtx context.Context, v *Viewport, clear bool) error {
+	if v == nil {
+		if !clear {
+			return nil
+		}
+		if err := emulation.ClearDeviceMetricsOverride().Do(tabCtx); err != nil {
+			return err
+		}
+		return emulation.SetTouchEmulationEnabled(false).Do(tabCtx)
+	}
+
+	dpr := v.DeviceScaleFactor
+	if dpr <= 0 {
+		dpr = 1
+	}
+	err := emulation.SetDeviceMetricsOverride(v.Width, v.Height, dpr, v.Mobile).
+		WithScreenWidth(v.Width).
+		WithScreenHeight(v.Height).
+		Do(tabCtx)
+	if err != nil {
+		return err
+	}
+	touch := emulation.SetTouchEmulationEnabled(v.Touch)
+	if v.Touch {
+		touch = touch.WithMaxTouchPoints(5)
+	}
+	return touch.Do(tabCtx)
+}
+
+// applyUserAgent sets the user agent (from the device preset) and the
+// Accept-Language header/navigator.languages (from the locale) in one call,
+// since each Emulation.setUserAgentOverride replaces both.
+func applyUserAgent(tabCtx context.Context, e Emulation, clear bool) error {
+	var ua string
+	if e.Viewport != nil {
+		ua = e.Viewport.UserAgent
+	}
+	if ua == "" && e.Locale == "" && !clear {
+		return nil
+	}
+	return emulation.SetUserAgentOverride(ua).WithAcceptLanguage(acceptLanguage(e.Locale)).Do(tabCtx)
+}
+
+// acceptLanguage turns "de-DE" into "de-DE,de;q=0.9".
+func acceptLanguage(locale string) string {
+	if lang, _, ok := strings.Cut(locale, "-"); ok {
+		return locale + "," + lang + ";q=0.9"
+	}
+	return locale
+}
+
+var localeRE = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// SetLocale overrides the ICU locale (Intl, toLocaleString) and the
+// Accept-Language header on every tab; "" clears the override.
+func (c *Controller) SetLocale(ctx context.Context, locale string) error {
+	if locale != "" && !localeRE.MatchString(locale) {
+		return fmt.Errorf("invalid locale %q (want a BCP 47 tag like en-US)", locale)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.emulation
+	next := c.emulation
+	next.Locale = locale
+	err := c.applyToTabsLocked(func(ctx context.Context) error {
+		if err := applyLocale(ctx, locale, prev.Locale != ""); err != nil {
+			return err
+		}
+		return applyUserAgent(ctx, next, prev.Locale != "")
+	}, func(ctx context.Context) error {
+		if err := applyLocale(ctx, prev.Locale, true); err != nil {
+			return err
+		}
+		return applyUserAgent(ctx, prev, true)
+	})
+	if err != nil {
+		return err
+	}
+	c.emulation = next
+	return nil
+}
+
+func applyLocale(tabCtx context.Context, locale string, clear bool) error {
+	if locale == "" && !clear {
+		return nil
+	}
+	// Chrome rejects a new locale while another override is in effect.
+	if clear {
+		if err := emulation.SetLocaleOverride().Do(tabCtx); err != nil || locale == "" {
+			return err
+		}
+	}
+	return emulation.SetLocaleOverride().WithLocale(locale).Do(tabCtx)
+}
+
+// SetTimezone overrides the timezone on every tab; "" clears the override.
+// Chrome validates the ID, so an unknown zone leaves the previous value.
+func (c *Controller) SetTimezone(ctx context.Context, tz string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.emulation.Timezone
+	err := c.applyToTabsLocked(
+		func(ctx context.Context) error { return applyTimezone(ctx, tz, prev != "") },
+		func(ctx context.Context) error { return applyTimezone(ctx, prev, true) })
+	if err != nil {
+		return err
+	}
+	c.emulation.Timezone = tz
+	return nil
+}
+
+func applyTimezone(tabCtx context.Context, tz string, clear bool) error {
+	if tz == "" && !clear {
+		return nil
+	}
+	return emulation.SetTimezoneOverride(tz).Do(tabCtx)
+}
+
+// SetGeolocation overrides navigator.geolocation on every tab and grants the
+// geolocation permission; nil clears the override.
+func (c *Controller) SetGeolocation(ctx context.Context, g *Geolocation) error {
+	if g != nil {
+		if g.Latitude < -90 || g.Latitude > 90 || g.Longitude < -180 || g.Longitude > 180 {
+			return fmt.Errorf("invalid coordinates %g,%g", g.Latitude, g.Longitude)
+		}
+		if g.Accuracy < 0 {
+			return errors.New("accuracy must not be negative")
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.emulation.Geolocation
+	err := c.applyToTabsLocked(
+		func(ctx context.Context) error { return applyGeolocation(ctx, g, prev != nil) },
+		func(ctx context.Context) error { return applyGeolocation(ctx, prev, true) })
+	if err != nil {
+		return err
+	}
+	c.emulation.Geolocation = g
+	return nil
+}
+
+func applyGeolocation(tabCtx context.Context, g *Geolocation, clear bool) error {
+	if g == nil {
+		if !clear {
+			return nil
+		}
+		return emulation.ClearGeolocationOverride().Do(tabCtx)
+	}
+	// Without the permission the page would be left waiting on a prompt.
+	if cc := chromedp.FromContext(tabCtx); cc != nil && cc.Browser != nil {
+		_ = cdpbrowser.GrantPermissions([]cdpbrowser.PermissionType{cdpbrowser.PermissionTypeGeolocation}).
+			Do(cdp.WithExecutor(tabCtx, cc.Browser))
+	}
+	accuracy := g.Accuracy
+	if accuracy <= 0 {
+		accuracy = 1
+	}
+	return emulation.SetGeolocationOverride().
+		WithLatitude(g.Latitude).
+		WithLongitude(g.Longitude).
+		WithAccuracy(accuracy).
+		Do(tabCtx)
+}
+
+// SetMedia overrides CSS media type/features on every tab; nil clears the
+// override. The override sticks to the target, so it survives reloads.
+func (c *Controller) SetMedia(ctx context.Context, m *Media) error {
+	if m != nil {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		if m.IsZero() {
+			m = nil
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.emulation.Media
+	err := c.applyToTabsLocked(
+		func(ctx context.Context) error { return applyMedia(ctx, m, prev != nil) },
+		func(ctx context.Context) error { return applyMedia(ctx, prev, true) })
+	if err != nil {
+		return err
+	}
+	c.emulation.Media = m
+	return nil
+}
+
+func applyMedia(tabCtx context.Context, m *Media, clear bool) error {
+	if m == nil {
+		if !clear {
+			return nil
+		}
+		// Without media type and features, Chrome drops the override.
+		return emulation.SetEmulatedMedia().Do(tabCtx)
+	}
+	// Each call replaces the full feature set.
+	var features []*emulation.MediaFeature
+	for _, f := range m.fields()[1:] {
+		if f.value != "" {
+			features = append(features, &emulation.MediaFeature{Name: f.name, Value: f.value})
+		}
+	}
+	return emulation.SetEmulatedMedia().WithMedia(m.Type).WithFeatures(features).Do(tabCtx)
+}
+
+// applyEmulationLocked re-applies all active overrides to a (new) tab. Like
+// the other apply functions it expects an executor context (see runOnTab).
+func (c *Controller) applyEmulationLocked(tabCtx context.Context) error {
+	e := c.emulation
+	if err := applyViewport(tabCtx, e.Viewport, false); err != nil {
+		return err
+	}
+	if err := applyUserAgent(tabCtx, e, false); err != nil {
+		return err
+	}
+	if err := applyMedia(tabCtx, e.Media, false); err != nil {
+		return err
+	}
+	if err := applyLocale(tabCtx, e.Locale, false); err != nil {
+		return err
+	}
+	if err := applyTimezone(tabCtx, e.Timezone, false); err != nil {
+		return err
+	}
+	if err := applyGeolocation(tabCtx, e.Geolocation, false); err != nil {
+		return err
+	}
+	return applyNetworkConditions(tabCtx, e.Network, false)
+}
@@ -0,0 +1,38 @@
+package browser
+
+import "testing"
+
+func TestAcceptLanguage(t *testing.T) {
+	for in, want := range map[string]string{
+		"":           "",
+		"fr":         "fr",
+		"de-DE":      "de-DE,de;q=0.9",
+		"zh-Hant-TW": "zh-Hant-TW,zh;q=0.9",
+	} {
+		if got := acceptLanguage(in); got != want {
+			t.Fatalf("acceptLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocaleRE(t *testing.T) {
+	for _, ok := range []string{"en", "en-US", "zh-Hant-TW", "es-419"} {
+		if !localeRE.MatchString(ok) {
+			t.Fatalf("expected %q to be valid", ok)
+		}
+	}
+	for _, bad := range []string{"english", "en_US", "de-", "1234"} {
+		if localeRE.MatchString(bad) {
+			t.Fatalf("expected %q to be invalid", bad)
+		}
+	}
+}
+
+func TestMediaValidate(t *testing.T) {
+	if err := (Media{Type: "print", ColorScheme: "dark", ForcedColors: "active"}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Media{ReducedMotion: "sometimes"}).Validate(); err == nil {
+		t.Fatal("expected error")
+	}
+}
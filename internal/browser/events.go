@@ -0,0 +1,29 @@
+package browser
+
+// Subscribe registers fn for every CDP event (cdproto event structs) of every
+// tab, including tabs of a relaunched browser. fn runs on the event
+// dispatcher: it must not block or call back into the Controller. The returned
+// func removes the subscription.
+func (c *Controller) Subscribe(fn func(tabID string, ev any)) (unsubscribe func()) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if c.subs == nil {
+		c.subs = map[int]func(string, any){}
+	}
+	id := c.nextID
+	c.nextID++
+	c.subs[id] = fn
+	return func() {
+		c.subMu.Lock()
+		delete(c.subs, id)
+		c.subMu.Unlock()
+	}
+}
+
+func (c *Controller) publish(tabID string, ev any) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	for _, fn := range c.subs {
+		fn(tabID, ev)
+	}
+}
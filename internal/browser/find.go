@@ -0,0 +1,153 @@
+package browser
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// chromiumFinder abstracts the filesystem/PATH probes used for browser discovery
+// so the search order can be tested against a fake environment.
+type chromiumFinder struct {
+	lookPath func(name string) (string, error)
+	stat     func(path string) (os.FileInfo, error)
+	glob     func(pattern string) ([]string, error)
+	getenv   func(key string) string
+	home     string
+}
+
+func newOSChromiumFinder() chromiumFinder {
+	home, _ := os.UserHomeDir()
+	return chromiumFinder{
+		lookPath: exec.LookPath,
+		stat:     os.Stat,
+		glob:     filepath.Glob,
+		getenv:   os.Getenv,
+		home:     home,
+	}
+}
+
+func (f chromiumFinder) isExecutable(p string) bool {
+	fi, err := f.stat(p)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	return fi.Mode().Perm()&0o111 != 0
+}
+
+// findLinux probes, in order: PATH names, well-known install dirs, snap/flatpak
+// wrappers and the Playwright/puppeteer browser caches.
+func (f chromiumFinder) findLinux() (string, error) {
+	var checked []string
+
+	for _, name := range []string{"chromium", "chromium-browser", "google-chrome-stable", "google-chrome", "headless_shell", "chrome"} {
+		checked = append(checked, "$PATH/"+name)
+		if p, err := f.lookPath(name); err == nil {
+			return p, nil
+		}
+	}
+
+	candidates := []string{
+		"/usr/bin/chromium",
+		"/usr/bin/chromium-browser",
+		"/usr/bin/google-chrome-stable",
+		"/usr/bin/google-chrome",
+		"/usr/lib/chromium/chromium",
+		"/usr/lib/chromium-browser/chromium-browser",
+		"/usr/local/bin/chromium",
+		"/opt/google/chrome/chrome",
+		"/opt/chromium.org/chromium/chromium",
+		"/opt/microsoft/msedge/msedge",
+		"/opt/brave.com/brave/brave",
+
+		// snap
+		"/snap/bin/chromium",
+		"/var/lib/snapd/snap/bin/chromium",
+
+		// flatpak (system-wide exports)
+		"/var/lib/flatpak/exports/bin/org.chromium.Chromium",
+		"/var/lib/flatpak/exports/bin/com.google.Chrome",
+	}
+	if f.home != "" {
+		candidates = append(candidates,
+			filepath.Join(f.home, ".local/share/flatpak/exports/bin/org.chromium.Chromium"),
+			filepath.Join(f.home, ".local/share/flatpak/exports/bin/com.google.Chrome"),
+		)
+	}
+	for _, p := range candidates {
+		checked = append(checked, p)
+		if f.isExecutable(p) {
+			return p, nil
+		}
+	}
+
+	for _, pattern := range f.cachePatterns() {
+		checked = append(checked, pattern)
+		matches, _ := f.glob(pattern)
+		// Prefer the newest version.
+		slices.SortFunc(matches, func(a, b string) int {
+			return slices.Compare(versionNumbers(b), versionNumbers(a))
+		})
+		for _, p := range matches {
+			if f.isExecutable(p) {
+				return p, nil
+			}
+		}
+	}
+
+	return "", errors.New("no Chromium/Chrome browser found (set --browser-bin); checked:\n  " + strings.Join(checked, "\n  "))
+}
+
+// versionNumbers returns the numbers in a cache path, e.g. [131 0 6778 85 64]
+// for .../linux-131.0.6778.85/chrome-linux64/chrome. Paths matching the same
+// pattern differ only in the version, so comparing these orders them by it.
+func versionNumbers(path string) []int {
+	var out []int
+	n, inNumber := 0, false
+	for _, r := range path + "/" {
+		if r >= '0' && r <= '9' {
+			n, inNumber = n*10+int(r-'0'), true
+			continue
+		}
+		if inNumber {
+			out = append(out, n)
+		}
+		n, inNumber = 0, false
+	}
+	return out
+}
+
+func (f chromiumFinder) cachePatterns() []string {
+	var playwrightRoots []string
+	if p := f.getenv("PLAYWRIGHT_BROWSERS_PATH"); p != "" && p != "0" {
+		playwrightRoots = append(playwrightRoots, p)
+	}
+	var puppeteerRoots []string
+	if p := f.getenv("PUPPETEER_CACHE_DIR"); p != "" {
+		puppeteerRoots = append(puppeteerRoots, p)
+	}
+	if f.home != "" {
+		playwrightRoots = append(playwrightRoots, filepath.Join(f.home, ".cache/ms-playwright"))
+		puppeteerRoots = append(puppeteerRoots, filepath.Join(f.home, ".cache/puppeteer"))
+	}
+
+	var out []string
+	for _, root := range playwrightRoots {
+		out = append(out,
+			filepath.Join(root, "chromium-*/chrome-linux64/chrome"),
+			filepath.Join(root, "chromium-*/chrome-linux/chrome"),
+			filepath.Join(root, "chromium_headless_shell-*/chrome-headless-shell-linux64/chrome-headless-shell"),
+			filepath.Join(root, "chromium_headless_shell-*/chrome-linux/headless_shell"),
+		)
+	}
+	for _, root := range puppeteerRoots {
+		out = append(out,
+			filepath.Join(root, "chrome/linux-*/chrome-linux64/chrome"),
+			filepath.Join(root, "chrome-headless-shell/linux-*/chrome-headless-shell-linux64/chrome-headless-shell"),
+		)
+	}
+	return out
+}
@@ -0,0 +1,7 @@
+//go:build linux
+
+package browser
+
+func FindChromiumBinary() (string, error) {
+	return newOSChromiumFinder().findLinux()
+}
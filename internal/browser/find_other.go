@@ -1,9 +1,9 @@
-//go:build !darwin
+//go:build !darwin && !linux
 
 package browser
 
 import "errors"
 
 func FindChromiumBinary() (string, error) {
-	return "", errors.New("FindChromiumBinary is only implemented on darwin (set --browser-bin)")
+	return "", errors.New("FindChromiumBinary is only implemented on darwin and linux (set --browser-bin)")
 }
@@ -0,0 +1,140 @@
+package browser
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func fakeFinder(files fstest.MapFS, path map[string]string) chromiumFinder {
+	rel := func(p string) string { return strings.TrimPrefix(p, "/") }
+	return chromiumFinder{
+		lookPath: func(name string) (string, error) {
+			if p, ok := path[name]; ok {
+				return p, nil
+			}
+			return "", errors.New("not found")
+		},
+		stat: func(p string) (os.FileInfo, error) {
+			return fs.Stat(files, rel(p))
+		},
+		glob: func(pattern string) ([]string, error) {
+			matches, err := fs.Glob(files, rel(pattern))
+			for i := range matches {
+				matches[i] = "/" + matches[i]
+			}
+			return matches, err
+		},
+		getenv: func(string) string { return "" },
+		home:   "/home/me",
+	}
+}
+
+func TestFindLinux_PrefersPATH(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{
+		"usr/bin/chromium": {Mode: 0o755},
+	}, map[string]string{"google-chrome-stable": "/custom/google-chrome-stable"})
+
+	got, err := f.findLinux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/custom/google-chrome-stable" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFindLinux_WellKnownAndSnap(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{
+		"snap/bin/chromium":         {Mode: 0o755},
+		"opt/google/chrome/chrome":  {Mode: 0o644}, // not executable
+		"usr/lib/chromium/chromium": {Mode: fs.ModeDir | 0o755},
+	}, nil)
+
+	got, err := f.findLinux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/snap/bin/chromium" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFindLinux_Flatpak(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{
+		"home/me/.local/share/flatpak/exports/bin/org.chromium.Chromium": {Mode: 0o755},
+	}, nil)
+
+	got, err := f.findLinux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/home/me/.local/share/flatpak/exports/bin/org.chromium.Chromium" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFindLinux_PlaywrightCachePrefersNewest(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{
+		"home/me/.cache/ms-playwright/chromium-999/chrome-linux64/chrome":  {Mode: 0o755},
+		"home/me/.cache/ms-playwright/chromium-1100/chrome-linux/chrome":   {Mode: 0o755},
+		"home/me/.cache/ms-playwright/chromium-1200/chrome-linux64/chrome": {Mode: 0o755},
+		"home/me/.cache/ms-playwright/chromium-1300/chrome-linux64/chrome": {Mode: 0o644},
+	}, nil)
+
+	got, err := f.findLinux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/home/me/.cache/ms-playwright/chromium-1200/chrome-linux64/chrome" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFindLinux_PuppeteerCachePrefersNewest(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{
+		"home/me/.cache/puppeteer/chrome/linux-99.0.4844.51/chrome-linux64/chrome":  {Mode: 0o755},
+		"home/me/.cache/puppeteer/chrome/linux-131.0.6778.85/chrome-linux64/chrome": {Mode: 0o755},
+		"home/me/.cache/puppeteer/chrome/linux-131.0.678.9/chrome-linux64/chrome":   {Mode: 0o755},
+	}, nil)
+
+	got, err := f.findLinux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/home/me/.cache/puppeteer/chrome/linux-131.0.6778.85/chrome-linux64/chrome" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFindLinux_PuppeteerCache(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{
+		"home/me/.cache/puppeteer/chrome/linux-131.0.6778.85/chrome-linux64/chrome": {Mode: 0o755},
+	}, nil)
+
+	got, err := f.findLinux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "/chrome-linux64/chrome") {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFindLinux_ReportsCheckedCandidates(t *testing.T) {
+	f := fakeFinder(fstest.MapFS{}, nil)
+
+	_, err := f.findLinux()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	msg := err.Error()
+	for _, want := range []string{"--browser-bin", "$PATH/chromium", "/usr/bin/chromium-browser", "/snap/bin/chromium", "ms-playwright", "puppeteer"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("error missing %q:\n%s", want, msg)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+)
+
+// InterceptedRequest is a request paused by the Fetch domain.
+type InterceptedRequest struct {
+	Tab          string
+	URL          string
+	Method       string
+	ResourceType string
+	Headers      map[string]string
+	PostData     []byte
+}
+
+// InterceptResult answers an intercepted request: either a response
+// (Status > 0) or a network error (Fail, a Network.ErrorReason such as
+// "Failed", "ConnectionRefused" or "InternetDisconnected").
+type InterceptResult struct {
+	Status  int64
+	Headers map[string]string
+	Body    []byte
+	Fail    string
+	// Delay holds the answer back, e.g. to simulate a slow endpoint.
+	Delay time.Duration
+}
+
+// Interceptor decides how to answer a request. Returning false lets the
+// request continue to the network unchanged. It runs on its own goroutine per
+// request and must not call back into the Controller.
+type Interceptor func(InterceptedRequest) (InterceptResult, bool)
+
+// SetInterceptor routes every request of every tab through fn (via the Fetch
+// domain); nil turns interception off.
+func (c *Controller) SetInterceptor(ctx context.Context, fn Interceptor) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fn == nil {
+		c.interceptor.Store(nil)
+	} else {
+		c.interceptor.Store(&fn)
+	}
+	for _, t := range c.tabs {
+		if err := runOnTab(t.ctx, func(ctx context.Context) error { return applyInterception(ctx, fn != nil) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyInterception(tabCtx context.Context, on bool) error {
+	if !on {
+		return fetch.Disable().Do(tabCtx)
+	}
+	return fetch.Enable().
+		WithPatterns([]*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageRequest}}).
+		Do(tabCtx)
+}
+
+// handlePaused answers a paused request. It runs on its own goroutine: the
+// answer must not wait on the event dispatcher, and it must not take c.mu,
+// which a navigation waiting on this very request may hold.
+func (c *Controller) handlePaused(tabCtx context.Context, tabID string, e *fetch.EventRequestPaused) {
+	var (
+		res InterceptResult
+		ok  bool
+	)
+	if fn := c.interceptor.Load(); fn != nil && e.Request != nil {
+		res, ok = (*fn)(InterceptedRequest{
+			Tab:          tabID,
+			URL:          e.Request.URL + e.Request.URLFragment,
+			Method:       e.Request.Method,
+			ResourceType: string(e.ResourceType),
+			Headers:      headerMap(e.Request.Headers),
+			PostData:     postData(e.Request),
+		})
+	}
+	if ok && res.Delay > 0 {
+		select {
+		case <-time.After(res.Delay):
+		case <-tabCtx.Done():
+			return
+		}
+	}
+	_ = runOnTab(tabCtx, func(ctx context.Context) error {
+		switch {
+		case !ok:
+			return fetch.ContinueRequest(e.RequestID).Do(ctx)
+		case res.Fail != "":
+			return fetch.FailRequest(e.RequestID, network.ErrorReason(res.Fail)).Do(ctx)
+		default:
+			return fetch.FulfillRequest(e.RequestID, res.Status).
+				WithResponseHeaders(headerEntries(res.Headers)).
+				WithBody(base64.StdEncoding.EncodeToString(res.Body)).
+				Do(ctx)
+		}
+	})
+}
+
+func headerMap(h network.Headers) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// headerEntries converts headers for Fetch.fulfillRequest. Multi-value headers
+// (e.g. Set-Cookie) are newline-separated, as Chrome reports them.
+func headerEntries(h map[string]string) []*fetch.HeaderEntry {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]*fetch.HeaderEntry, 0, len(h))
+	for _, k := range keys {
+		for _, v := range strings.Split(h[k], "\n") {
+			out = append(out, &fetch.HeaderEntry{Name: http.CanonicalHeaderKey(k), Value: v})
+		}
+	}
+	return out
+}
+
+func postData(r *network.Request) []byte {
+	var out []byte
+	for _, e := range r.PostDataEntries {
+		b, err := base64.StdEncoding.DecodeString(e.Bytes)
+		if err != nil {
+			continue
+		}
+		out = append(out, b...)
+	}
+	return out
+}
@@ -30,7 +30,7 @@ type LaunchOptions struct {
 }
 
 type launchedBrowser struct {
-	Cmd          *exec.Cmd
+	Proc         *process
 	DevToolsWS   string
 	DevToolsPort int
 	TargetID     target.ID
@@ -59,13 +59,14 @@ func launch(ctx context.Context, opts LaunchOptions) (launchedBrowser, error) {
 		log.Printf("launching browser: %s %s", opts.BrowserBin, strings.Join(args, " "))
 	}
 
-	if err := cmd.Start(); err != nil {
+	proc, err := startProcess(cmd)
+	if err != nil {
 		return launchedBrowser{}, err
 	}
 
 	ws, err := DevToolsWebSocketURL(opts.DevToolsPort)
 	if err != nil {
-		_ = terminateProcess(cmd, 2*time.Second)
+		_ = proc.terminate(2 * time.Second)
 		return launchedBrowser{}, err
 	}
 
@@ -77,7 +78,7 @@ func launch(ctx context.Context, opts LaunchOptions) (launchedBrowser, error) {
 	}
 
 	return launchedBrowser{
-		Cmd:          cmd,
+		Proc:         proc,
 		DevToolsWS:   ws,
 		DevToolsPort: opts.DevToolsPort,
 		TargetID:     tgtID,
@@ -164,21 +165,60 @@ func newRemoteTabContext(parent context.Context, wsURL string, targetID target.I
 	return tabCtx, cancel, nil
 }
 
-func terminateProcess(cmd *exec.Cmd, timeout time.Duration) error {
-	if cmd == nil || cmd.Process == nil {
+// process owns the single cmd.Wait call for a launched browser so that both the
+// crash monitor and shutdown can observe its exit.
+type process struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error // valid once done is closed
+}
+
+func startProcess(cmd *exec.Cmd) (*process, error) {
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &process{cmd: cmd, done: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+func (p *process) Pid() int {
+	if p == nil || p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// Done is closed once the process has exited.
+func (p *process) Done() <-chan struct{} {
+	if p == nil {
 		return nil
 	}
+	return p.done
+}
+
+func (p *process) exitReason() string {
+	if p.err != nil {
+		return "browser process exited: " + p.err.Error()
+	}
+	return "browser process exited"
+}
 
-	_ = cmd.Process.Signal(syscall.SIGTERM)
-	done := make(chan error, 1)
-	go func() { done <- cmd.Wait() }()
+func (p *process) terminate(timeout time.Duration) error {
+	if p == nil || p.cmd.Process == nil {
+		return nil
+	}
 
+	_ = p.cmd.Process.Signal(syscall.SIGTERM)
 	select {
-	case err := <-done:
-		return err
+	case <-p.done:
+		return p.err
 	case <-time.After(timeout):
-		_ = cmd.Process.Kill()
-		<-done
+		_ = p.cmd.Process.Kill()
+		<-p.done
 		return context.DeadlineExceeded
 	}
 }
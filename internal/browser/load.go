@@ -0,0 +1,121 @@
+package browser
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// LoadReport summarizes what went wrong (if anything) while a page loaded.
+type LoadReport struct {
+	URL    string
+	Title  string
+	Status int64 // HTTP status of the main document; 0 if unknown (e.g. cached, file://)
+
+	FailedRequests []FailedRequest
+	// Errors holds uncaught exceptions and console errors raised during the load.
+	Errors []LogEntry
+}
+
+// FailedRequest is a subresource that failed to load or returned an HTTP error.
+type FailedRequest struct {
+	URL    string
+	Type   string // resource type (Script, Stylesheet, Image, …)
+	Status int64  // HTTP status; 0 for network-level failures
+	Error  string // e.g. net::ERR_NAME_NOT_RESOLVED, or the blocked reason
+}
+
+// loadCollector gathers the events of one tab while a navigation runs.
+type loadCollector struct {
+	tabID string
+
+	mu       sync.Mutex
+	status   int64
+	requests map[network.RequestID]*network.Request
+	failed   []FailedRequest
+	errors   []LogEntry
+}
+
+func newLoadCollector(tabID string) *loadCollector {
+	return &loadCollector{tabID: tabID, requests: map[network.RequestID]*network.Request{}}
+}
+
+func (l *loadCollector) handle(tabID string, ev any) {
+	if tabID != l.tabID {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch e := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		l.requests[e.RequestID] = e.Request
+	case *network.EventResponseReceived:
+		if e.Response == nil {
+			return
+		}
+		// The main frame's ID is the tab's target ID.
+		if e.Type == network.ResourceTypeDocument && e.FrameID == cdp.FrameID(l.tabID) {
+			l.status = e.Response.Status
+			return
+		}
+		if e.Response.Status >= 400 {
+			l.failed = append(l.failed, FailedRequest{
+				URL:    e.Response.URL,
+				Type:   string(e.Type),
+				Status: e.Response.Status,
+				Error:  e.Response.StatusText,
+			})
+		}
+	case *network.EventLoadingFailed:
+		if e.Canceled {
+			return
+		}
+		f := FailedRequest{Type: string(e.Type), Error: e.ErrorText}
+		if e.BlockedReason != "" {
+			f.Error = "blocked: " + string(e.BlockedReason)
+		}
+		if req := l.requests[e.RequestID]; req != nil {
+			f.URL = req.URL
+		}
+		l.failed = append(l.failed, f)
+	default:
+		entry, ok := LogEntryFromEvent(ev)
+		// "Failed to load resource" network log entries duplicate failed requests.
+		if ok && entry.Level == LevelError && entry.Source != "network" {
+			l.errors = append(l.errors, entry)
+		}
+	}
+}
+
+func (l *loadCollector) report() LoadReport {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return LoadReport{Status: l.status, FailedRequests: l.failed, Errors: l.errors}
+}
+
+// runLoadLocked runs a navigation action on the tab and reports status,
+// failed requests and page errors observed until the body is ready.
+func (c *Controller) runLoadLocked(ctx context.Context, action chromedp.Action) (LoadReport, error) {
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return LoadReport{}, err
+	}
+	col := newLoadCollector(string(chromedp.FromContext(tabCtx).Target.TargetID))
+	unsubscribe := c.Subscribe(col.handle)
+	defer unsubscribe()
+
+	var loc, title string
+	err = chromedp.Run(tabCtx,
+		action,
+		chromedp.WaitReady("body", chromedp.ByQuery),
+		chromedp.Location(&loc),
+		chromedp.Title(&title),
+	)
+	r := col.report()
+	r.URL, r.Title = loc, title
+	return r, err
+}
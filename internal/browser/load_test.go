@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/cdp"
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestLoadCollector(t *testing.T) {
+	l := newLoadCollector("TAB1")
+	events := []any{
+		&network.EventResponseReceived{Type: network.ResourceTypeDocument, FrameID: cdp.FrameID("TAB1"), Response: &network.Response{URL: "http://x/", Status: 200}},
+		// An iframe document is a subresource.
+		&network.EventResponseReceived{Type: network.ResourceTypeDocument, FrameID: "CHILD", Response: &network.Response{URL: "http://x/frame", Status: 500, StatusText: "Internal Server Error"}},
+		&network.EventResponseReceived{Type: network.ResourceTypeStylesheet, FrameID: "TAB1", Response: &network.Response{URL: "http://x/app.css", Status: 404, StatusText: "Not Found"}},
+		&network.EventResponseReceived{Type: network.ResourceTypeImage, FrameID: "TAB1", Response: &network.Response{URL: "http://x/logo.png", Status: 200}},
+		&network.EventRequestWillBeSent{RequestID: "r1", Request: &network.Request{URL: "http://cdn.invalid/lib.js"}},
+		&network.EventLoadingFailed{RequestID: "r1", Type: network.ResourceTypeScript, ErrorText: "net::ERR_NAME_NOT_RESOLVED"},
+		&network.EventLoadingFailed{RequestID: "r2", Canceled: true},
+		&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{Text: "Uncaught"}},
+		&runtime.EventConsoleAPICalled{Type: runtime.APITypeLog, Args: []*runtime.RemoteObject{{Type: runtime.TypeString, Value: []byte(`"hi"`)}}},
+		&cdplog.EventEntryAdded{Entry: &cdplog.Entry{Source: cdplog.SourceNetwork, Level: cdplog.LevelError, Text: "Failed to load resource"}},
+	}
+	for _, ev := range events {
+		l.handle("TAB1", ev)
+	}
+	// Events of other tabs are ignored.
+	l.handle("TAB2", &runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{Text: "Uncaught"}})
+
+	r := l.report()
+	if r.Status != 200 {
+		t.Fatalf("status = %d", r.Status)
+	}
+	if len(r.FailedRequests) != 3 {
+		t.Fatalf("failed = %+v", r.FailedRequests)
+	}
+	if f := r.FailedRequests[2]; f.URL != "http://cdn.invalid/lib.js" || f.Type != "Script" || f.Error != "net::ERR_NAME_NOT_RESOLVED" {
+		t.Fatalf("network failure = %+v", f)
+	}
+	if len(r.Errors) != 1 || r.Errors[0].Source != "exception" {
+		t.Fatalf("errors = %+v", r.Errors)
+	}
+}
@@ -0,0 +1,177 @@
+package browser
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+// Log levels, lowest to highest.
+const (
+	LevelDebug   = "debug"
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+// LevelRank orders levels for filtering; unknown levels rank as info.
+func LevelRank(level string) int {
+	switch level {
+	case LevelDebug, "verbose":
+		return 0
+	case LevelWarning, "warn":
+		return 2
+	case LevelError:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// LogEntry is a console message, uncaught exception or browser log entry.
+type LogEntry struct {
+	Time   time.Time
+	Level  string
+	Source string // "console", "exception", or the Log domain source ("network", "security", …)
+	Type   string // console API method (log, warn, error, …), if any
+	Text   string
+	URL    string
+	Line   int64 // 1-based; 0 if unknown
+	Column int64 // 1-based; 0 if unknown
+	Stack  []StackFrame
+}
+
+type StackFrame struct {
+	Function string
+	URL      string
+	Line     int64 // 1-based
+	Column   int64 // 1-based
+}
+
+// LogEntryFromEvent converts Runtime.consoleAPICalled, Runtime.exceptionThrown
+// and Log.entryAdded events; ok is false for any other event.
+func LogEntryFromEvent(ev any) (entry LogEntry, ok bool) {
+	switch e := ev.(type) {
+	case *runtime.EventConsoleAPICalled:
+		args := make([]string, 0, len(e.Args))
+		for _, a := range e.Args {
+			args = append(args, remoteObjectString(a))
+		}
+		entry = LogEntry{
+			Time:   timestamp(e.Timestamp),
+			Level:  consoleLevel(e.Type),
+			Source: "console",
+			Type:   string(e.Type),
+			Text:   strings.Join(args, " "),
+			Stack:  stackFrames(e.StackTrace),
+		}
+	case *runtime.EventExceptionThrown:
+		d := e.ExceptionDetails
+		if d == nil {
+			return LogEntry{}, false
+		}
+		text := d.Text
+		if d.Exception != nil && d.Exception.Description != "" {
+			// The description is "TypeError: msg\n    at …"; the stack is reported separately.
+			desc, _, _ := strings.Cut(d.Exception.Description, "\n")
+			text = strings.TrimSpace(text + " " + desc)
+		}
+		entry = LogEntry{
+			Time:   timestamp(e.Timestamp),
+			Level:  LevelError,
+			Source: "exception",
+			Text:   text,
+			URL:    d.URL,
+			Line:   d.LineNumber + 1,
+			Column: d.ColumnNumber + 1,
+			Stack:  stackFrames(d.StackTrace),
+		}
+	case *cdplog.EventEntryAdded:
+		le := e.Entry
+		if le == nil {
+			return LogEntry{}, false
+		}
+		level := string(le.Level)
+		if level == "verbose" {
+			level = LevelDebug
+		}
+		entry = LogEntry{
+			Time:   timestamp(le.Timestamp),
+			Level:  level,
+			Source: string(le.Source),
+			Text:   le.Text,
+			URL:    le.URL,
+			Stack:  stackFrames(le.StackTrace),
+		}
+		if le.LineNumber > 0 {
+			entry.Line = le.LineNumber + 1
+		}
+	default:
+		return LogEntry{}, false
+	}
+	if entry.URL == "" && len(entry.Stack) > 0 {
+		top := entry.Stack[0]
+		entry.URL, entry.Line, entry.Column = top.URL, top.Line, top.Column
+	}
+	return entry, true
+}
+
+func consoleLevel(t runtime.APIType) string {
+	switch t {
+	case runtime.APITypeError, runtime.APITypeAssert:
+		return LevelError
+	case runtime.APITypeWarning:
+		return LevelWarning
+	case runtime.APITypeDebug:
+		return LevelDebug
+	default:
+		return LevelInfo
+	}
+}
+
+func timestamp(ts *runtime.Timestamp) time.Time {
+	if ts == nil {
+		return time.Now()
+	}
+	return ts.Time()
+}
+
+func stackFrames(st *runtime.StackTrace) []StackFrame {
+	if st == nil {
+		return nil
+	}
+	out := make([]StackFrame, 0, len(st.CallFrames))
+	for _, f := range st.CallFrames {
+		out = append(out, StackFrame{
+			Function: f.FunctionName,
+			URL:      f.URL,
+			Line:     f.LineNumber + 1,
+			Column:   f.ColumnNumber + 1,
+		})
+	}
+	return out
+}
+
+// remoteObjectString renders a console argument roughly like DevTools does.
+func remoteObjectString(o *runtime.RemoteObject) string {
+	if o == nil {
+		return ""
+	}
+	if len(o.Value) > 0 {
+		var s string
+		if o.Type == runtime.TypeString && json.Unmarshal(o.Value, &s) == nil {
+			return s
+		}
+		return string(o.Value)
+	}
+	if o.UnserializableValue != "" {
+		return string(o.UnserializableValue)
+	}
+	if o.Description != "" {
+		return o.Description
+	}
+	return string(o.Type)
+}
@@ -0,0 +1,81 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestLogEntryFromEvent_Console(t *testing.T) {
+	ts := runtime.Timestamp(time.UnixMilli(1700000000000))
+	e, ok := LogEntryFromEvent(&runtime.EventConsoleAPICalled{
+		Type: runtime.APITypeWarning,
+		Args: []*runtime.RemoteObject{
+			{Type: runtime.TypeString, Value: []byte(`"slow frame"`)},
+			{Type: runtime.TypeNumber, Value: []byte(`42`)},
+			{Type: runtime.TypeNumber, UnserializableValue: "NaN"},
+			{Type: runtime.TypeObject, Description: "Object"},
+		},
+		Timestamp: &ts,
+		StackTrace: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{
+			{FunctionName: "tick", URL: "http://127.0.0.1:1/app.js", LineNumber: 9, ColumnNumber: 4},
+		}},
+	})
+	if !ok {
+		t.Fatal("not converted")
+	}
+	if e.Level != LevelWarning || e.Source != "console" || e.Type != "warning" || e.Text != "slow frame 42 NaN Object" {
+		t.Fatalf("entry = %#v", e)
+	}
+	if !e.Time.Equal(time.UnixMilli(1700000000000)) {
+		t.Fatalf("time = %v", e.Time)
+	}
+	if e.URL != "http://127.0.0.1:1/app.js" || e.Line != 10 || e.Column != 5 {
+		t.Fatalf("location = %s:%d:%d", e.URL, e.Line, e.Column)
+	}
+}
+
+func TestLogEntryFromEvent_Exception(t *testing.T) {
+	e, ok := LogEntryFromEvent(&runtime.EventExceptionThrown{
+		ExceptionDetails: &runtime.ExceptionDetails{
+			Text:         "Uncaught",
+			URL:          "http://127.0.0.1:1/app.js",
+			LineNumber:   2,
+			ColumnNumber: 7,
+			Exception:    &runtime.RemoteObject{Type: runtime.TypeObject, Description: "TypeError: x is not a function\n    at http://127.0.0.1:1/app.js:3:8"},
+			StackTrace: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{
+				{URL: "http://127.0.0.1:1/app.js", LineNumber: 2, ColumnNumber: 7},
+			}},
+		},
+	})
+	if !ok {
+		t.Fatal("not converted")
+	}
+	if e.Level != LevelError || e.Source != "exception" || e.Text != "Uncaught TypeError: x is not a function" {
+		t.Fatalf("entry = %#v", e)
+	}
+	if e.Line != 3 || e.Column != 8 || len(e.Stack) != 1 {
+		t.Fatalf("location = %d:%d stack=%v", e.Line, e.Column, e.Stack)
+	}
+}
+
+func TestLogEntryFromEvent_LogDomain(t *testing.T) {
+	e, ok := LogEntryFromEvent(&cdplog.EventEntryAdded{Entry: &cdplog.Entry{
+		Source: cdplog.SourceNetwork,
+		Level:  cdplog.LevelError,
+		Text:   "Failed to load resource: the server responded with a status of 404 (Not Found)",
+		URL:    "http://127.0.0.1:1/missing.css",
+	}})
+	if !ok {
+		t.Fatal("not converted")
+	}
+	if e.Level != LevelError || e.Source != "network" || e.URL != "http://127.0.0.1:1/missing.css" || e.Line != 0 {
+		t.Fatalf("entry = %#v", e)
+	}
+
+	if _, ok := LogEntryFromEvent(&runtime.EventExecutionContextsCleared{}); ok {
+		t.Fatal("unexpected conversion")
+	}
+}
@@ -0,0 +1,306 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// NetworkRequest is one request as seen through the Network domain.
+type NetworkRequest struct {
+	ID        string
+	Tab       string
+	URL       string
+	Method    string
+	Type      string // resource type (Document, Script, XHR, Fetch, …)
+	Status    int64
+	MIMEType  string
+	Size      int64 // encoded bytes received
+	Started   time.Time
+	Duration  time.Duration // until loading finished/failed
+	Initiator string        // e.g. "parser http://x/index.html:12" or "script http://x/app.js:3"
+	Failure   string        // net::ERR_…, "blocked: …" or "canceled"
+	FromCache bool
+	Finished  bool
+
+	// Details for HAR export.
+	RequestHeaders  map[string]string
+	PostData        []byte
+	StatusText      string
+	Protocol        string // e.g. "http/1.1", "h2"
+	ResponseHeaders map[string]string
+	RemoteIP        string
+	RedirectURL     string // set on the hop a redirect replaced
+	Timing          *Timing
+
+	start cdp.MonotonicTime
+}
+
+// Timing breaks a request down into HAR phases (ms; -1 if not applicable).
+// Receive is derived from Duration.
+type Timing struct {
+	Blocked float64
+	DNS     float64
+	Connect float64 // includes SSL
+	SSL     float64
+	Send    float64
+	Wait    float64
+}
+
+// Failed reports a network-level failure or an HTTP error status.
+func (r NetworkRequest) Failed() bool {
+	return r.Failure != "" || r.Status >= 400
+}
+
+// NetworkRecorder aggregates Network.* events into per-request records. Feed
+// it via Controller.Subscribe.
+type NetworkRecorder struct {
+	// PerNavigation drops a tab's records when its main frame starts loading
+	// a new document.
+	PerNavigation bool
+	// Max bounds the records kept per tab (oldest dropped first); 0 means no limit.
+	Max int
+
+	mu   sync.Mutex
+	tabs map[string]*tabRequests
+}
+
+type tabRequests struct {
+	list []*NetworkRequest
+	byID map[string]*NetworkRequest
+}
+
+// Handle consumes one CDP event of a tab.
+func (r *NetworkRecorder) Handle(tabID string, ev any) {
+	switch ev.(type) {
+	case *network.EventRequestWillBeSent, *network.EventResponseReceived,
+		*network.EventLoadingFinished, *network.EventLoadingFailed,
+		*network.EventRequestServedFromCache:
+	default:
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tabs == nil {
+		r.tabs = map[string]*tabRequests{}
+	}
+	t := r.tabs[tabID]
+	if t == nil {
+		t = &tabRequests{byID: map[string]*NetworkRequest{}}
+		r.tabs[tabID] = t
+	}
+
+	switch e := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		id := string(e.RequestID)
+		if prev := t.byID[id]; prev != nil && e.RedirectResponse != nil {
+			// A redirect reuses the request ID; close out the previous hop.
+			applyResponse(prev, e.RedirectResponse)
+			prev.Size = int64(e.RedirectResponse.EncodedDataLength)
+			prev.Finished = true
+			prev.Duration = since(prev.start, e.Timestamp)
+			if e.Request != nil {
+				prev.RedirectURL = e.Request.URL
+			}
+		} else if r.PerNavigation && e.Type == network.ResourceTypeDocument &&
+			e.FrameID == cdp.FrameID(tabID) && string(e.LoaderID) == id {
+			t.list = nil
+			t.byID = map[string]*NetworkRequest{}
+		}
+		if e.Request == nil {
+			return
+		}
+		req := &NetworkRequest{
+			ID:        id,
+			Tab:       tabID,
+			URL:       e.Request.URL + e.Request.URLFragment,
+			Method:    e.Request.Method,
+			Type:      string(e.Type),
+			Initiator: initiatorString(e.Initiator),
+
+			RequestHeaders: headerMap(e.Request.Headers),
+			PostData:       postData(e.Request),
+		}
+		if e.WallTime != nil {
+			req.Started = e.WallTime.Time()
+		}
+		if e.Timestamp != nil {
+			req.start = *e.Timestamp
+		}
+		t.byID[id] = req
+		t.list = append(t.list, req)
+		if r.Max > 0 && len(t.list) > r.Max {
+			drop := t.list[0]
+			t.list = t.list[1:]
+			if t.byID[drop.ID] == drop {
+				delete(t.byID, drop.ID)
+			}
+		}
+	case *network.EventRequestServedFromCache:
+		if req := t.byID[string(e.RequestID)]; req != nil {
+			req.FromCache = true
+		}
+	case *network.EventResponseReceived:
+		req := t.byID[string(e.RequestID)]
+		if req == nil || e.Response == nil {
+			return
+		}
+		applyResponse(req, e.Response)
+		if req.Type == "" {
+			req.Type = string(e.Type)
+		}
+	case *network.EventLoadingFinished:
+		if req := t.byID[string(e.RequestID)]; req != nil {
+			req.Size = int64(e.EncodedDataLength)
+			req.Finished = true
+			req.Duration = since(req.start, e.Timestamp)
+		}
+	case *network.EventLoadingFailed:
+		if req := t.byID[string(e.RequestID)]; req != nil {
+			switch {
+			case e.BlockedReason != "":
+				req.Failure = "blocked: " + string(e.BlockedReason)
+			case e.Canceled:
+				req.Failure = "canceled"
+			default:
+				req.Failure = e.ErrorText
+			}
+			req.Finished = true
+			req.Duration = since(req.start, e.Timestamp)
+		}
+	}
+}
+
+func applyResponse(req *NetworkRequest, resp *network.Response) {
+	req.Status = resp.Status
+	req.StatusText = resp.StatusText
+	req.MIMEType = resp.MimeType
+	req.Protocol = resp.Protocol
+	req.ResponseHeaders = headerMap(resp.Headers)
+	req.RemoteIP = resp.RemoteIPAddress
+	if resp.FromDiskCache || resp.FromPrefetchCache {
+		req.FromCache = true
+	}
+	if len(resp.RequestHeaders) > 0 {
+		// The headers actually sent, including ones added by the network stack.
+		req.RequestHeaders = headerMap(resp.RequestHeaders)
+	}
+	if rt := resp.Timing; rt != nil {
+		req.Timing = harTiming(rt)
+	}
+}
+
+// harTiming converts Chrome's ResourceTiming (ms offsets from requestTime,
+// -1 if not applicable) into HAR phases.
+func harTiming(rt *network.ResourceTiming) *Timing {
+	span := func(start, end float64) float64 {
+		if start < 0 || end < 0 {
+			return -1
+		}
+		return end - start
+	}
+	blocked := rt.SendStart
+	for _, v := range []float64{rt.ConnectStart, rt.DNSStart} {
+		if v >= 0 {
+			blocked = v
+		}
+	}
+	return &Timing{
+		Blocked: max(blocked, 0),
+		DNS:     span(rt.DNSStart, rt.DNSEnd),
+		Connect: span(rt.ConnectStart, rt.ConnectEnd),
+		SSL:     span(rt.SslStart, rt.SslEnd),
+		Send:    max(span(rt.SendStart, rt.SendEnd), 0),
+		Wait:    max(span(rt.SendEnd, rt.ReceiveHeadersEnd), 0),
+	}
+}
+
+// Requests returns copies of the recorded requests of one tab ("" for all
+// tabs), oldest first.
+func (r *NetworkRecorder) Requests(tabID string) []NetworkRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []NetworkRequest
+	for id, t := range r.tabs {
+		if tabID != "" && id != tabID {
+			continue
+		}
+		for _, req := range t.list {
+			out = append(out, *req)
+		}
+	}
+	if tabID == "" {
+		slices.SortStableFunc(out, func(a, b NetworkRequest) int { return a.Started.Compare(b.Started) })
+	}
+	return out
+}
+
+// Lookup finds a request by ID across tabs (the latest hop for redirects).
+func (r *NetworkRecorder) Lookup(id string) (NetworkRequest, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tabs {
+		if req := t.byID[id]; req != nil {
+			return *req, true
+		}
+	}
+	return NetworkRequest{}, false
+}
+
+// Reset drops all records.
+func (r *NetworkRecorder) Reset() {
+	r.mu.Lock()
+	r.tabs = nil
+	r.mu.Unlock()
+}
+
+func since(start cdp.MonotonicTime, end *cdp.MonotonicTime) time.Duration {
+	if end == nil || time.Time(start).IsZero() {
+		return 0
+	}
+	return end.Time().Sub(start.Time())
+}
+
+func initiatorString(in *network.Initiator) string {
+	if in == nil {
+		return ""
+	}
+	s := string(in.Type)
+	switch {
+	case in.URL != "":
+		s += fmt.Sprintf(" %s:%d", in.URL, int64(in.LineNumber)+1)
+	case in.Stack != nil && len(in.Stack.CallFrames) > 0:
+		f := in.Stack.CallFrames[0]
+		s += fmt.Sprintf(" %s:%d", f.URL, f.LineNumber+1)
+	}
+	return s
+}
+
+// ResponseBody fetches a response body of the tab in ctx (see WithTab).
+// Bodies are only available while the page that loaded them is alive.
+func (c *Controller) ResponseBody(ctx context.Context, requestID string) ([]byte, error) {
+	if requestID == "" {
+		return nil, errors.New("missing request id")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	err = chromedp.Run(tabCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		body, err = network.GetResponseBody(network.RequestID(requestID)).Do(ctx)
+		return err
+	}))
+	return body, err
+}
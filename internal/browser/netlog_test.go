@@ -0,0 +1,94 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func mono(sec float64) *cdp.MonotonicTime {
+	t := cdp.MonotonicTime(time.Unix(0, int64(sec*float64(time.Second))))
+	return &t
+}
+
+func navigation(tab, id, url string, at float64) *network.EventRequestWillBeSent {
+	return &network.EventRequestWillBeSent{
+		RequestID: network.RequestID(id), LoaderID: cdp.LoaderID(id), FrameID: cdp.FrameID(tab),
+		Type: network.ResourceTypeDocument, Timestamp: mono(at),
+		Request:   &network.Request{URL: url, Method: "GET"},
+		Initiator: &network.Initiator{Type: network.InitiatorTypeOther},
+	}
+}
+
+func TestNetworkRecorder(t *testing.T) {
+	r := &NetworkRecorder{PerNavigation: true}
+	for _, ev := range []any{
+		navigation("T", "old", "http://x/old", 0),
+		navigation("T", "doc", "http://x/", 1),
+		&network.EventResponseReceived{RequestID: "doc", Type: network.ResourceTypeDocument, Response: &network.Response{Status: 200, MimeType: "text/html"}},
+		&network.EventLoadingFinished{RequestID: "doc", Timestamp: mono(1.25), EncodedDataLength: 512},
+		&network.EventRequestWillBeSent{
+			RequestID: "2", FrameID: "T", Type: network.ResourceTypeFetch, Timestamp: mono(2),
+			Request:   &network.Request{URL: "http://x/api", Method: "POST"},
+			Initiator: &network.Initiator{Type: network.InitiatorTypeScript, Stack: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{{URL: "http://x/app.js", LineNumber: 3}}}},
+		},
+		// Redirect: same request ID, the previous hop gets the 302.
+		&network.EventRequestWillBeSent{
+			RequestID: "2", FrameID: "T", Type: network.ResourceTypeFetch, Timestamp: mono(2.1),
+			Request:          &network.Request{URL: "http://x/api/v2", Method: "POST"},
+			RedirectResponse: &network.Response{Status: 302},
+		},
+		&network.EventResponseReceived{RequestID: "2", Response: &network.Response{Status: 500, MimeType: "application/json"}},
+		&network.EventLoadingFinished{RequestID: "2", Timestamp: mono(2.3), EncodedDataLength: 20},
+		&network.EventRequestWillBeSent{RequestID: "3", FrameID: "T", Type: network.ResourceTypeFont, Timestamp: mono(3), Request: &network.Request{URL: "http://cdn.invalid/f.woff2", Method: "GET"}},
+		&network.EventLoadingFailed{RequestID: "3", Timestamp: mono(3.5), ErrorText: "net::ERR_NAME_NOT_RESOLVED"},
+	} {
+		r.Handle("T", ev)
+	}
+	r.Handle("OTHER", navigation("OTHER", "o", "http://y/", 0))
+
+	got := r.Requests("T")
+	if len(got) != 4 {
+		t.Fatalf("requests = %+v", got)
+	}
+	doc, hop, api, font := got[0], got[1], got[2], got[3]
+	if doc.URL != "http://x/" || doc.Status != 200 || doc.Size != 512 || doc.Duration != 250*time.Millisecond || !doc.Finished || doc.Initiator != "other" {
+		t.Fatalf("doc = %+v", doc)
+	}
+	if hop.URL != "http://x/api" || hop.Status != 302 || hop.Duration != 100*time.Millisecond {
+		t.Fatalf("redirect hop = %+v", hop)
+	}
+	if api.URL != "http://x/api/v2" || api.Status != 500 || !api.Failed() || api.MIMEType != "application/json" {
+		t.Fatalf("api = %+v", api)
+	}
+	if hop.Initiator != "script http://x/app.js:4" {
+		t.Fatalf("initiator = %q", hop.Initiator)
+	}
+	if font.Failure != "net::ERR_NAME_NOT_RESOLVED" || !font.Failed() || font.Duration != 500*time.Millisecond {
+		t.Fatalf("font = %+v", font)
+	}
+
+	if req, ok := r.Lookup("2"); !ok || req.URL != "http://x/api/v2" {
+		t.Fatalf("lookup = %+v %v", req, ok)
+	}
+	if all := r.Requests(""); len(all) != 5 {
+		t.Fatalf("all tabs = %d requests", len(all))
+	}
+}
+
+func TestNetworkRecorder_Max(t *testing.T) {
+	r := &NetworkRecorder{Max: 2}
+	for i, id := range []string{"a", "b", "c"} {
+		r.Handle("T", &network.EventRequestWillBeSent{RequestID: network.RequestID(id), Timestamp: mono(float64(i)), Request: &network.Request{URL: "http://x/" + id}})
+	}
+	got := r.Requests("T")
+	if len(got) != 2 || got[0].ID != "b" {
+		t.Fatalf("requests = %+v", got)
+	}
+	if _, ok := r.Lookup("a"); ok {
+		t.Fatal("evicted request still found")
+	}
+}
@@ -0,0 +1,76 @@
+package browser
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/chromedp/cdproto/page"
+)
+
+// PDFOptions configures Controller.PDF. Sizes are in inches; zero values keep
+// Chrome's defaults (US Letter, scale 1).
+type PDFOptions struct {
+	PaperWidth  float64
+	PaperHeight float64
+	Margins     *PDFMargins // nil keeps Chrome's 1cm
+	Landscape   bool
+	Scale       float64
+	Background  bool // print background graphics
+	// Header and footer are HTML templates; elements with the classes date,
+	// title, url, pageNumber and totalPages get the print values.
+	HeaderTemplate    string
+	FooterTemplate    string
+	PageRanges        string // e.g. "1-3, 5"; empty prints all pages
+	PreferCSSPageSize bool   // let @page size win over the paper size
+}
+
+type PDFMargins struct {
+	Top, Right, Bottom, Left float64
+}
+
+// defaultPDFMargin is Chrome's default; CDP takes a missing margin as 0.
+const defaultPDFMargin = 1 / 2.54
+
+func pdfParams(opts PDFOptions) *page.PrintToPDFParams {
+	m := PDFMargins{defaultPDFMargin, defaultPDFMargin, defaultPDFMargin, defaultPDFMargin}
+	if opts.Margins != nil {
+		m = *opts.Margins
+	}
+	p := page.PrintToPDF().
+		WithLandscape(opts.Landscape).
+		WithPrintBackground(opts.Background).
+		WithScale(opts.Scale).
+		WithPaperWidth(opts.PaperWidth).
+		WithPaperHeight(opts.PaperHeight).
+		WithMarginTop(m.Top).
+		WithMarginRight(m.Right).
+		WithMarginBottom(m.Bottom).
+		WithMarginLeft(m.Left).
+		WithPageRanges(opts.PageRanges).
+		WithPreferCSSPageSize(opts.PreferCSSPageSize)
+	if opts.HeaderTemplate != "" || opts.FooterTemplate != "" {
+		// Chrome fills a missing template with its own (date, title, URL).
+		empty := "<span></span>"
+		p = p.WithDisplayHeaderFooter(true).
+			WithHeaderTemplate(cmp.Or(opts.HeaderTemplate, empty)).
+			WithFooterTemplate(cmp.Or(opts.FooterTemplate, empty))
+	}
+	return p
+}
+
+// PDF prints the current page to PDF.
+func (c *Controller) PDF(ctx context.Context, opts PDFOptions) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var buf []byte
+	err = runOnTab(tabCtx, func(ctx context.Context) error {
+		var err error
+		buf, _, err = pdfParams(opts).Do(ctx)
+		return err
+	})
+	return buf, err
+}
@@ -0,0 +1,29 @@
+package browser
+
+import "testing"
+
+func TestPDFParams(t *testing.T) {
+	p := pdfParams(PDFOptions{})
+	if p.MarginTop != defaultPDFMargin || p.MarginLeft != defaultPDFMargin || p.DisplayHeaderFooter {
+		t.Fatalf("defaults = %+v", p)
+	}
+
+	p = pdfParams(PDFOptions{
+		PaperWidth:     8.27,
+		PaperHeight:    11.69,
+		Margins:        &PDFMargins{Top: 1, Right: 0, Bottom: 0.5, Left: 0},
+		Landscape:      true,
+		Scale:          0.8,
+		HeaderTemplate: `<div class="title"></div>`,
+	})
+	if p.PaperWidth != 8.27 || p.PaperHeight != 11.69 || !p.Landscape || p.Scale != 0.8 {
+		t.Fatalf("params = %+v", p)
+	}
+	if p.MarginTop != 1 || p.MarginRight != 0 || p.MarginBottom != 0.5 || p.MarginLeft != 0 {
+		t.Fatalf("margins = %+v", p)
+	}
+	// A header alone must not bring back Chrome's default footer.
+	if !p.DisplayHeaderFooter || p.HeaderTemplate != `<div class="title"></div>` || p.FooterTemplate != "<span></span>" {
+		t.Fatalf("templates = %+v", p)
+	}
+}
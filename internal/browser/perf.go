@@ -0,0 +1,114 @@
+package browser
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// PerfReport is a performance snapshot of the current page. Page timings and
+// vitals are in ms since navigation start; -1 means not observed (yet).
+type PerfReport struct {
+	URL string
+	// Metrics are Chrome's Performance.getMetrics counters (JSHeapUsedSize,
+	// Nodes, LayoutCount, ScriptDuration, …; durations in seconds).
+	Metrics map[string]float64
+
+	TTFB             float64
+	FCP              float64
+	DOMContentLoaded float64
+	Load             float64
+	TransferSize     float64 // bytes of the main document
+
+	LCP float64
+	CLS float64
+	// INP approximates Interaction to Next Paint: the slowest interaction
+	// the page buffered (Chrome only buffers events of 104ms and more).
+	INP float64
+	FID float64
+}
+
+// perfScript reads navigation timing and collects buffered web-vitals
+// entries. Observers deliver buffered entries asynchronously, hence the
+// short wait.
+const perfScript = `(async () => {
+  const out = {url: location.href, ttfb: -1, fcp: -1, dcl: -1, load: -1, transfer: -1, lcp: -1, cls: 0, inp: -1, fid: -1};
+  const nav = performance.getEntriesByType('navigation')[0];
+  if (nav) {
+    out.ttfb = nav.responseStart;
+    out.dcl = nav.domContentLoadedEventEnd || -1;
+    out.load = nav.loadEventEnd || -1;
+    out.transfer = nav.transferSize;
+  }
+  const fcp = performance.getEntriesByName('first-contentful-paint')[0];
+  if (fcp) out.fcp = fcp.startTime;
+  const observe = (type, fn, opts) => {
+    try {
+      new PerformanceObserver((list) => list.getEntries().forEach(fn)).observe(Object.assign({type, buffered: true}, opts));
+    } catch (e) {}
+  };
+  observe('largest-contentful-paint', (e) => { out.lcp = e.renderTime || e.loadTime || e.startTime; });
+  observe('layout-shift', (e) => { if (!e.hadRecentInput) out.cls += e.value; });
+  observe('first-input', (e) => { out.fid = e.processingStart - e.startTime; });
+  observe('event', (e) => { if (e.interactionId) out.inp = Math.max(out.inp, e.duration); }, {durationThreshold: 16});
+  await new Promise((r) => setTimeout(r, 50));
+  return out;
+})()`
+
+// Perf collects a PerfReport for the current tab.
+func (c *Controller) Perf(ctx context.Context) (PerfReport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return PerfReport{}, err
+	}
+
+	var metrics []*performance.Metric
+	if err := runOnTab(tabCtx, func(ctx context.Context) error {
+		if err := performance.Enable().Do(ctx); err != nil {
+			return err
+		}
+		metrics, err = performance.GetMetrics().Do(ctx)
+		return err
+	}); err != nil {
+		return PerfReport{}, err
+	}
+
+	var js struct {
+		URL      string  `json:"url"`
+		TTFB     float64 `json:"ttfb"`
+		FCP      float64 `json:"fcp"`
+		DCL      float64 `json:"dcl"`
+		Load     float64 `json:"load"`
+		Transfer float64 `json:"transfer"`
+		LCP      float64 `json:"lcp"`
+		CLS      float64 `json:"cls"`
+		INP      float64 `json:"inp"`
+		FID      float64 `json:"fid"`
+	}
+	awaitPromise := func(p *runtime.EvaluateParams) *runtime.EvaluateParams { return p.WithAwaitPromise(true) }
+	if err := chromedp.Run(tabCtx, chromedp.Evaluate(perfScript, &js, awaitPromise)); err != nil {
+		return PerfReport{}, err
+	}
+
+	out := PerfReport{
+		URL:              js.URL,
+		Metrics:          make(map[string]float64, len(metrics)),
+		TTFB:             js.TTFB,
+		FCP:              js.FCP,
+		DOMContentLoaded: js.DCL,
+		Load:             js.Load,
+		TransferSize:     js.Transfer,
+		LCP:              js.LCP,
+		CLS:              js.CLS,
+		INP:              js.INP,
+		FID:              js.FID,
+	}
+	for _, m := range metrics {
+		out.Metrics[m.Name] = m.Value
+	}
+	return out, nil
+}
@@ -0,0 +1,167 @@
+package browser
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/chromedp/cdproto/heapprofiler"
+	"github.com/chromedp/cdproto/profiler"
+)
+
+// CPUProfile samples the tab's JavaScript for d and returns the profile
+// (serialized as JSON, it is a DevTools .cpuprofile). The tab isn't locked
+// while sampling, so other commands can drive the page meanwhile.
+func (c *Controller) CPUProfile(ctx context.Context, d, interval time.Duration) (*profiler.Profile, error) {
+	c.mu.Lock()
+	t, err := c.tabLocked(ctx)
+	if err == nil {
+		err = runOnTab(t.ctx, func(ctx context.Context) error {
+			if err := profiler.Enable().Do(ctx); err != nil {
+				return err
+			}
+			if interval > 0 {
+				if err := profiler.SetSamplingInterval(interval.Microseconds()).Do(ctx); err != nil {
+					return err
+				}
+			}
+			return profiler.Start().Do(ctx)
+		})
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+
+	// Stop even if ctx is done; a running profiler keeps sampling.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var profile *profiler.Profile
+	err = runOnTab(t.ctx, func(ctx context.Context) error {
+		var err error
+		profile, err = profiler.Stop().Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, ctx.Err()
+}
+
+// ProfileFunction is one function's share of a CPU profile.
+type ProfileFunction struct {
+	Function string
+	URL      string
+	Line     int64 // 1-based
+	Column   int64 // 1-based
+	Self     time.Duration
+	Samples  int64
+}
+
+// CPUProfileSummary condenses a CPU profile for the terminal.
+type CPUProfileSummary struct {
+	Duration time.Duration
+	Samples  int
+	Idle     time.Duration
+	Top      []ProfileFunction // by self time, without (idle)
+}
+
+// SummarizeCPUProfile attributes each sample's duration to the function on
+// top of the stack and returns the n functions with the most self time.
+func SummarizeCPUProfile(p *profiler.Profile, n int) CPUProfileSummary {
+	out := CPUProfileSummary{
+		Duration: time.Duration(p.EndTime-p.StartTime) * time.Microsecond,
+		Samples:  len(p.Samples),
+	}
+	nodes := make(map[int64]*profiler.ProfileNode, len(p.Nodes))
+	for _, node := range p.Nodes {
+		nodes[node.ID] = node
+	}
+
+	type key struct {
+		fn, url      string
+		line, column int64
+	}
+	byFunc := map[key]*ProfileFunction{}
+	for i, id := range p.Samples {
+		// A sample lasts until the next one; the last gets no time.
+		var dur time.Duration
+		if i+1 < len(p.TimeDeltas) {
+			dur = time.Duration(p.TimeDeltas[i+1]) * time.Microsecond
+		}
+		node := nodes[id]
+		if node == nil || node.CallFrame == nil {
+			continue
+		}
+		cf := node.CallFrame
+		if cf.FunctionName == "(idle)" {
+			out.Idle += dur
+			continue
+		}
+		k := key{cf.FunctionName, cf.URL, cf.LineNumber, cf.ColumnNumber}
+		f := byFunc[k]
+		if f == nil {
+			name := cf.FunctionName
+			if name == "" {
+				name = "(anonymous)"
+			}
+			f = &ProfileFunction{Function: name, URL: cf.URL, Line: cf.LineNumber + 1, Column: cf.ColumnNumber + 1}
+			byFunc[k] = f
+		}
+		f.Self += dur
+		f.Samples++
+	}
+
+	for _, f := range byFunc {
+		out.Top = append(out.Top, *f)
+	}
+	slices.SortFunc(out.Top, func(a, b ProfileFunction) int {
+		return cmp.Or(cmp.Compare(b.Self, a.Self), cmp.Compare(b.Samples, a.Samples), cmp.Compare(a.Function, b.Function))
+	})
+	if n > 0 && len(out.Top) > n {
+		out.Top = out.Top[:n]
+	}
+	return out
+}
+
+// HeapSnapshot takes a heap snapshot of the tab and writes it to w in the
+// DevTools .heapsnapshot format.
+func (c *Controller) HeapSnapshot(ctx context.Context, w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, err := c.tabLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Chunks arrive as events before takeHeapSnapshot returns. Buffer them:
+	// subscribers must not block on a slow writer.
+	var buf bytes.Buffer
+	tabID := string(t.id)
+	unsubscribe := c.Subscribe(func(id string, ev any) {
+		if e, ok := ev.(*heapprofiler.EventAddHeapSnapshotChunk); ok && id == tabID {
+			buf.WriteString(e.Chunk)
+		}
+	})
+	err = runOnTab(t.ctx, func(ctx context.Context) error {
+		if err := heapprofiler.Enable().Do(ctx); err != nil {
+			return err
+		}
+		defer func() { _ = heapprofiler.Disable().Do(ctx) }()
+		return heapprofiler.TakeHeapSnapshot().Do(ctx)
+	})
+	unsubscribe()
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
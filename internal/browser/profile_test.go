@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/profiler"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestSummarizeCPUProfile(t *testing.T) {
+	frame := func(name, url string, line int64) *runtime.CallFrame {
+		return &runtime.CallFrame{FunctionName: name, URL: url, LineNumber: line, ColumnNumber: 4}
+	}
+	p := &profiler.Profile{
+		Nodes: []*profiler.ProfileNode{
+			{ID: 1, CallFrame: frame("(root)", "", -1)},
+			{ID: 2, CallFrame: frame("(idle)", "", -1)},
+			{ID: 3, CallFrame: frame("render", "http://x/app.js", 9)},
+			{ID: 4, CallFrame: frame("", "http://x/app.js", 30)},
+			// The same function reached through another call path.
+			{ID: 5, CallFrame: frame("render", "http://x/app.js", 9)},
+		},
+		StartTime:  0,
+		EndTime:    10_000,
+		Samples:    []int64{3, 5, 4, 2, 2, 3},
+		TimeDeltas: []int64{0, 1000, 2000, 1000, 3000, 1500},
+	}
+	s := SummarizeCPUProfile(p, 5)
+	if s.Duration != 10*time.Millisecond || s.Samples != 6 || s.Idle != 4500*time.Microsecond {
+		t.Fatalf("summary = %+v", s)
+	}
+	if len(s.Top) != 2 {
+		t.Fatalf("top = %+v", s.Top)
+	}
+	render, anon := s.Top[0], s.Top[1]
+	if render.Function != "render" || render.Self != 3*time.Millisecond || render.Samples != 3 || render.Line != 10 || render.Column != 5 {
+		t.Fatalf("render = %+v", render)
+	}
+	if anon.Function != "(anonymous)" || anon.Self != time.Millisecond {
+		t.Fatalf("anonymous = %+v", anon)
+	}
+	if got := SummarizeCPUProfile(p, 1); len(got.Top) != 1 {
+		t.Fatalf("limit = %+v", got.Top)
+	}
+}
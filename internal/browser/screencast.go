@@ -0,0 +1,161 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+)
+
+// maxScreencastBytes bounds the frames a recording keeps in memory; later
+// frames are dropped.
+const maxScreencastBytes = 512 << 20
+
+// ScreencastOptions configures StartScreencast. Zero values keep Chrome's
+// defaults (the viewport size, every frame).
+type ScreencastOptions struct {
+	Quality       int // JPEG quality 0-100
+	MaxWidth      int
+	MaxHeight     int
+	EveryNthFrame int
+}
+
+// ScreencastFrame is a JPEG frame and the time it arrived.
+type ScreencastFrame struct {
+	Data []byte
+	Time time.Time
+}
+
+// Screencast is a finished recording. Chrome only sends frames when the page
+// changes, so the last frame lasts until End.
+type Screencast struct {
+	Frames  []ScreencastFrame
+	Start   time.Time
+	End     time.Time
+	Dropped int // frames over the memory limit
+}
+
+// screencastSession is the running recording (at most one per controller).
+// Frames are appended by the event subscription.
+type screencastSession struct {
+	tabID       string
+	start       time.Time
+	unsubscribe func()
+
+	mu      sync.Mutex
+	frames  []ScreencastFrame
+	bytes   int
+	dropped int
+}
+
+// StartScreencast starts recording the tab's frames.
+func (c *Controller) StartScreencast(ctx context.Context, opts ScreencastOptions) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.screencast != nil {
+		return fmt.Errorf("tab %s is already being recorded", c.screencast.tabID)
+	}
+	t, err := c.tabLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := &screencastSession{tabID: string(t.id), start: time.Now()}
+	tabCtx := t.ctx
+	s.unsubscribe = c.Subscribe(func(id string, ev any) {
+		e, ok := ev.(*page.EventScreencastFrame)
+		if !ok || id != s.tabID {
+			return
+		}
+		// Chrome waits for the ack before sending the next frame. Listeners
+		// can't issue CDP calls themselves.
+		go func() { _ = runOnTab(tabCtx, page.ScreencastFrameAck(e.SessionID).Do) }()
+
+		data, err := base64.StdEncoding.DecodeString(e.Data)
+		if err != nil {
+			return
+		}
+		at := time.Now()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.bytes+len(data) > maxScreencastBytes {
+			s.dropped++
+			return
+		}
+		s.bytes += len(data)
+		s.frames = append(s.frames, ScreencastFrame{Data: data, Time: at})
+	})
+
+	p := page.StartScreencast().WithFormat(page.ScreencastFormatJpeg)
+	if opts.Quality > 0 {
+		p = p.WithQuality(int64(opts.Quality))
+	}
+	if opts.MaxWidth > 0 {
+		p = p.WithMaxWidth(int64(opts.MaxWidth))
+	}
+	if opts.MaxHeight > 0 {
+		p = p.WithMaxHeight(int64(opts.MaxHeight))
+	}
+	if opts.EveryNthFrame > 0 {
+		p = p.WithEveryNthFrame(int64(opts.EveryNthFrame))
+	}
+	if err := runOnTab(t.ctx, p.Do); err != nil {
+		s.unsubscribe()
+		return err
+	}
+	c.screencast = s
+	return nil
+}
+
+// Screencasting reports the tab being recorded, if any.
+func (c *Controller) Screencasting() (tabID string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.screencast == nil {
+		return "", false
+	}
+	return c.screencast.tabID, true
+}
+
+// ScreencastSoFar returns the frames recorded so far, ending now, without
+// stopping the recording.
+func (c *Controller) ScreencastSoFar() (Screencast, bool) {
+	c.mu.Lock()
+	s := c.screencast
+	c.mu.Unlock()
+	if s == nil {
+		return Screencast{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Screencast{Frames: s.frames[:len(s.frames):len(s.frames)], Start: s.start, End: time.Now(), Dropped: s.dropped}, true
+}
+
+// StopScreencast ends the recording and returns its frames, also when the
+// recorded tab has been closed meanwhile.
+func (c *Controller) StopScreencast(ctx context.Context) (Screencast, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.screencast
+	if s == nil {
+		return Screencast{}, errors.New("no recording running")
+	}
+	c.screencast = nil
+	if t, err := c.findTabLocked(s.tabID); err == nil {
+		if err := runOnTab(t.ctx, page.StopScreencast().Do); err != nil {
+			s.unsubscribe()
+			return Screencast{}, err
+		}
+	}
+	s.unsubscribe()
+
+	out := Screencast{Frames: s.frames, Start: s.start, End: time.Now(), Dropped: s.dropped}
+	if len(out.Frames) == 0 {
+		return out, errors.New("no frames recorded")
+	}
+	return out, nil
+}
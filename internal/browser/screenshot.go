@@ -0,0 +1,253 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// ScreenshotOptions configures Controller.Screenshot. Without Selector, Clip
+// or FullPage it captures the viewport.
+type ScreenshotOptions struct {
+	Selector string
+	Format   string // png (default), jpeg or webp
+	Quality  int    // 0-100, jpeg and webp only; 0 keeps Chrome's default
+	FullPage bool   // the whole document, beyond the viewport
+	Clip     *Rect  // document coordinates in CSS pixels
+	// Scale resizes the capture (2 doubles the pixels); 0 means 1.
+	Scale          float64
+	OmitBackground bool    // transparent instead of white where the page paints nothing
+	Padding        float64 // CSS pixels around Selector
+}
+
+// Rect is a rectangle in CSS pixels.
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Validate reports options that can't be combined.
+func (o ScreenshotOptions) Validate() error {
+	switch o.Format {
+	case "", "png", "jpeg", "webp":
+	default:
+		return fmt.Errorf("unsupported format %q (want png, jpeg or webp)", o.Format)
+	}
+	modes := 0
+	for _, set := range []bool{o.Selector != "", o.Clip != nil, o.FullPage} {
+		if set {
+			modes++
+		}
+	}
+	switch {
+	case modes > 1:
+		return errors.New("selector, clip and full page are mutually exclusive")
+	case o.Quality != 0 && (o.Format == "" || o.Format == "png"):
+		return errors.New("quality only applies to jpeg and webp")
+	case o.Quality < 0 || o.Quality > 100:
+		return errors.New("quality must be between 0 and 100")
+	case o.OmitBackground && o.Format == "jpeg":
+		return errors.New("jpeg has no transparency; use png or webp to omit the background")
+	case o.Clip != nil && (o.Clip.Width <= 0 || o.Clip.Height <= 0):
+		return errors.New("clip needs a positive width and height")
+	case o.Scale < 0:
+		return errors.New("scale must be positive")
+	case o.Padding != 0 && o.Selector == "":
+		return errors.New("padding only applies to selector screenshots")
+	}
+	return nil
+}
+
+// elementRectScript returns the element's box in document coordinates.
+const elementRectScript = `(() => {
+  const r = document.querySelector(%q).getBoundingClientRect();
+  return {x: r.left + window.scrollX, y: r.top + window.scrollY, width: r.width, height: r.height};
+})()`
+
+// Screenshot captures the tab as described by opts.
+func (c *Controller) Screenshot(ctx context.Context, opts ScreenshotOptions) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return captureScreenshot(tabCtx, opts)
+}
+
+func captureScreenshot(tabCtx context.Context, opts ScreenshotOptions) ([]byte, error) {
+	var clip *Rect
+	switch {
+	case opts.Clip != nil:
+		r := *opts.Clip
+		clip = &r
+	case opts.Selector != "":
+		var r Rect
+		if err := chromedp.Run(tabCtx,
+			chromedp.WaitVisible(opts.Selector, chromedp.ByQuery),
+			chromedp.Evaluate(fmt.Sprintf(elementRectScript, opts.Selector), &r),
+		); err != nil {
+			return nil, err
+		}
+		r = Rect{r.X - opts.Padding, r.Y - opts.Padding, r.Width + 2*opts.Padding, r.Height + 2*opts.Padding}
+		// Padding can't reach above or left of the document.
+		if r.X < 0 {
+			r.Width += r.X
+			r.X = 0
+		}
+		if r.Y < 0 {
+			r.Height += r.Y
+			r.Y = 0
+		}
+		clip = &r
+	}
+
+	var buf []byte
+	err := runOnTab(tabCtx, func(ctx context.Context) error {
+		_, _, _, _, visual, content, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return err
+		}
+		switch {
+		case opts.FullPage:
+			clip = &Rect{0, 0, math.Ceil(content.Width), math.Ceil(content.Height)}
+		case clip == nil && opts.Scale != 0 && opts.Scale != 1:
+			clip = &Rect{visual.PageX, visual.PageY, visual.ClientWidth, visual.ClientHeight}
+		}
+
+		if opts.OmitBackground {
+			if err := emulation.SetDefaultBackgroundColorOverride().WithColor(&cdp.RGBA{}).Do(ctx); err != nil {
+				return err
+			}
+			defer func() { _ = emulation.SetDefaultBackgroundColorOverride().Do(ctx) }()
+		}
+
+		p := page.CaptureScreenshot().WithFromSurface(true)
+		if opts.Format != "" {
+			p = p.WithFormat(page.CaptureScreenshotFormat(opts.Format))
+		}
+		if opts.Quality > 0 {
+			p = p.WithQuality(int64(opts.Quality))
+		}
+		if clip != nil {
+			scale := opts.Scale
+			if scale == 0 {
+				scale = 1
+			}
+			p = p.WithClip(&page.Viewport{X: clip.X, Y: clip.Y, Width: clip.Width, Height: clip.Height, Scale: scale}).
+				WithCaptureBeyondViewport(opts.FullPage || opts.Selector != "" || opts.Clip != nil)
+		}
+		buf, err = p.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// maxScreenshotWidth bounds the widths ScreenshotWidths accepts (Chrome's
+// own device metrics limit).
+const maxScreenshotWidth = 10_000_000
+
+// WidthScreenshot is a full-page screenshot at one viewport width.
+type WidthScreenshot struct {
+	Width int64
+	Data  []byte
+}
+
+// settleScript resolves once the page has laid out and painted again.
+const settleScript = `new Promise(r => requestAnimationFrame(() => requestAnimationFrame(() => r(true))))`
+
+// ScreenshotWidths takes a full-page screenshot with the viewport overridden
+// to each width in turn, keeping the current viewport height (and an
+// emulated device's scale factor and mobile mode), and restores the viewport
+// (or the emulated one) afterwards. Other options apply to every
+// capture; Selector and Clip can't be combined with it.
+func (c *Controller) ScreenshotWidths(ctx context.Context, widths []int64, opts ScreenshotOptions) (shots []WidthScreenshot, err error) {
+	if len(widths) == 0 {
+		return nil, errors.New("no widths given")
+	}
+	for _, w := range widths {
+		if w <= 0 || w > maxScreenshotWidth {
+			return nil, fmt.Errorf("invalid width %d", w)
+		}
+	}
+	if opts.Selector != "" || opts.Clip != nil {
+		return nil, errors.New("widths capture the full page; they can't be combined with a selector or clip")
+	}
+	opts.FullPage = true
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tabCtx, err := c.tabCtxLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	emulated := c.emulation.Viewport
+	// A scale factor of 0 keeps the screen's own.
+	height, dpr, mobile := int64(0), 0.0, false
+	if emulated != nil {
+		height, mobile = emulated.Height, emulated.Mobile
+		dpr = emulated.DeviceScaleFactor
+		if dpr <= 0 {
+			dpr = 1 // as applyViewport does
+		}
+	} else {
+		err := runOnTab(tabCtx, func(ctx context.Context) error {
+			_, _, _, layout, _, _, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return err
+			}
+			height = layout.ClientHeight
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer func() {
+		// The tab context outlives ctx, so this runs even if ctx is done.
+		rerr := runOnTab(tabCtx, func(ctx context.Context) error { return applyViewport(ctx, emulated, true) })
+		if rerr != nil && err == nil {
+			shots, err = nil, fmt.Errorf("restore viewport: %w", rerr)
+		}
+	}()
+	awaitPromise := func(p *runtime.EvaluateParams) *runtime.EvaluateParams { return p.WithAwaitPromise(true) }
+	for _, w := range widths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := chromedp.Run(tabCtx,
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				return emulation.SetDeviceMetricsOverride(w, height, dpr, mobile).
+					WithScreenWidth(w).
+					WithScreenHeight(height).
+					Do(ctx)
+			}),
+			chromedp.Evaluate(settleScript, nil, awaitPromise),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("width %d: %w", w, err)
+		}
+		buf, err := captureScreenshot(tabCtx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("width %d: %w", w, err)
+		}
+		shots = append(shots, WidthScreenshot{Width: w, Data: buf})
+	}
+	return shots, nil
+}
@@ -0,0 +1,33 @@
+package browser
+
+import "testing"
+
+func TestScreenshotOptionsValidate(t *testing.T) {
+	ok := []ScreenshotOptions{
+		{},
+		{Selector: "#app", Padding: 8, Format: "webp", Quality: 80, OmitBackground: true},
+		{FullPage: true, Format: "jpeg", Quality: 60, Scale: 0.5},
+		{Clip: &Rect{X: 0, Y: 100, Width: 300, Height: 200}},
+	}
+	for _, o := range ok {
+		if err := o.Validate(); err != nil {
+			t.Errorf("%+v: %v", o, err)
+		}
+	}
+	bad := []ScreenshotOptions{
+		{Format: "gif"},
+		{Selector: "#app", FullPage: true},
+		{Clip: &Rect{Width: 10, Height: 10}, FullPage: true},
+		{Quality: 80},
+		{Format: "jpeg", Quality: 101},
+		{Format: "jpeg", OmitBackground: true},
+		{Clip: &Rect{Width: 0, Height: 10}},
+		{Scale: -1},
+		{Padding: 4},
+	}
+	for _, o := range bad {
+		if err := o.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", o)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/inspector"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+type tab struct {
+	id  target.ID
+	ctx context.Context
+	// cancel closes the tab; nil for the tab whose context owns the
+	// DevTools connection (closing it must not drop the connection).
+	cancel context.CancelFunc
+
+	mu  sync.Mutex // guards url; updated from CDP event listeners
+	url string
+}
+
+func (t *tab) URL() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.url
+}
+
+type TabInfo struct {
+	ID     string
+	URL    string
+	Title  string
+	Active bool
+}
+
+type tabKey struct{}
+
+// WithTab returns a context that makes Controller methods operate on the given
+// tab (ID or unique ID prefix) instead of the active one.
+func WithTab(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tabKey{}, id)
+}
+
+func tabFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tabKey{}).(string)
+	return id
+}
+
+// tabCtxLocked resolves the chromedp context for the tab requested via WithTab,
+// falling back to the active tab. Callers must hold c.mu.
+func (c *Controller) tabCtxLocked(ctx context.Context) (context.Context, error) {
+	t, err := c.tabLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.ctx, nil
+}
+
+// tabLocked is tabCtxLocked returning the tab itself. Callers must hold c.mu.
+func (c *Controller) tabLocked(ctx context.Context) (*tab, error) {
+	id := tabFromContext(ctx)
+	if id == "" {
+		if c.active == nil {
+			return nil, errors.New("no active tab")
+		}
+		return c.active, nil
+	}
+	return c.findTabLocked(id)
+}
+
+// TabID resolves the tab selected by ctx (see WithTab; default: the active
+// tab) to its full target ID.
+func (c *Controller) TabID(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, err := c.tabLocked(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(t.id), nil
+}
+
+func (c *Controller) findTabLocked(id string) (*tab, error) {
+	var match *tab
+	for _, t := range c.tabs {
+		if string(t.id) == id {
+			return t, nil
+		}
+		if strings.HasPrefix(string(t.id), id) {
+			if match != nil {
+				return nil, fmt.Errorf("ambiguous tab id %q", id)
+			}
+			match = t
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("unknown tab %q", id)
+	}
+	return match, nil
+}
+
+// initTabLocked wires up per-tab listeners and settings. Callers must hold c.mu.
+func (c *Controller) initTabLocked(t *tab) {
+	onCrash := c.onTargetCrash
+	chromedp.ListenTarget(t.ctx, func(ev any) {
+		switch e := ev.(type) {
+		case *page.EventFrameNavigated:
+			if e.Frame != nil && e.Frame.ParentID == "" {
+				t.mu.Lock()
+				t.url = e.Frame.URL + e.Frame.URLFragment
+				t.mu.Unlock()
+			}
+		case *inspector.EventTargetCrashed:
+			// Only the root tab's crash takes the browser down with it; other
+			// tabs are just dropped. Listeners can't take c.mu themselves.
+			if t.cancel == nil {
+				if onCrash != nil {
+					onCrash()
+				}
+			} else {
+				go c.dropCrashedTab(t, onCrash)
+			}
+		case *fetch.EventRequestPaused:
+			go c.handlePaused(t.ctx, string(t.id), e)
+		}
+		c.publish(string(t.id), ev)
+	})
+	if c.opts.Stealth {
+		_ = runOnTab(t.ctx, applyStealth)
+	}
+	_ = runOnTab(t.ctx, c.applyEmulationLocked)
+	if c.interceptor.Load() != nil {
+		_ = runOnTab(t.ctx, func(ctx context.Context) error { return applyInterception(ctx, true) })
+	}
+}
+
+// dropCrashedTab closes a crashed tab other than the root one. If it was the
+// last tab left, the browser is reported as crashed instead.
+func (c *Controller) dropCrashedTab(t *tab, onCrash func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !slices.Contains(c.tabs, t) {
+		return
+	}
+	if len(c.tabs) == 1 {
+		if onCrash != nil {
+			onCrash()
+		}
+		return
+	}
+	log.Printf("tab %s crashed; closing it", t.id)
+	t.cancel()
+	c.removeTabLocked(t)
+}
+
+// removeTabLocked forgets t, moving on to a neighbouring tab if it was the
+// active one. Callers must hold c.mu.
+func (c *Controller) removeTabLocked(t *tab) {
+	idx := slices.Index(c.tabs, t)
+	if idx < 0 {
+		return
+	}
+	c.tabs = slices.Delete(c.tabs, idx, idx+1)
+	if c.active == t {
+		c.active = nil
+		if len(c.tabs) > 0 {
+			c.active = c.tabs[min(idx, len(c.tabs)-1)]
+		}
+	}
+}
+
+// runOnTab runs raw CDP commands against a tab. cdproto's Do needs the tab's
+// executor in the context, which chromedp.Run installs.
+func runOnTab(tabCtx context.Context, fn func(ctx context.Context) error) error {
+	return chromedp.Run(tabCtx, chromedp.ActionFunc(fn))
+}
+
+func (c *Controller) closeTabsLocked() {
+	for _, t := range c.tabs {
+		if t.cancel != nil {
+			t.cancel()
+		}
+	}
+	c.tabs = nil
+	c.active = nil
+}
+
+// ListTabs returns the page targets of the browser, including ones the page
+// opened itself (e.g. via window.open).
+func (c *Controller) ListTabs(ctx context.Context) ([]TabInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.listTabsLocked(ctx)
+}
+
+func (c *Controller) listTabsLocked(ctx context.Context) ([]TabInfo, error) {
+	infos, err := c.pageTargetsLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Forget tabs that were closed behind our back (e.g. by the user).
+	alive := make(map[target.ID]bool, len(infos))
+	for _, ti := range infos {
+		alive[ti.TargetID] = true
+	}
+	kept := c.tabs[:0]
+	for _, t := range c.tabs {
+		if alive[t.id] {
+			kept = append(kept, t)
+		} else if t.cancel != nil {
+			t.cancel()
+		}
+	}
+	c.tabs = kept
+	if c.active != nil && !alive[c.active.id] {
+		c.active = nil
+		if len(c.tabs) > 0 {
+			c.active = c.tabs[0]
+		}
+	}
+
+	out := make([]TabInfo, 0, len(infos))
+	for _, ti := range infos {
+		out = append(out, TabInfo{
+			ID:     string(ti.TargetID),
+			URL:    ti.URL,
+			Title:  ti.Title,
+			Active: c.active != nil && c.active.id == ti.TargetID,
+		})
+	}
+	return out, nil
+}
+
+func (c *Controller) pageTargetsLocked(ctx context.Context) ([]*target.Info, error) {
+	cc := chromedp.FromContext(c.conn)
+	if cc == nil || cc.Browser == nil {
+		return nil, errors.New("browser not connected")
+	}
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	all, err := target.GetTargets().Do(cdp.WithExecutor(runCtx, cc.Browser))
+	if err != nil {
+		return nil, err
+	}
+	var out []*target.Info
+	for _, ti := range all {
+		if ti.Type == "page" {
+			out = append(out, ti)
+		}
+	}
+	return out, nil
+}
+
+// NewTab opens a new tab, navigates it to url (if set) and makes it active.
+func (c *Controller) NewTab(ctx context.Context, url string) (TabInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return TabInfo{}, errors.New("browser not connected")
+	}
+
+	// Share the existing DevTools connection, but don't tie the tab's
+	// lifetime to the connection-owning tab.
+	tabCtx, cancel := chromedp.NewContext(context.WithoutCancel(c.conn))
+	if err := chromedp.Run(tabCtx); err != nil {
+		cancel()
+		return TabInfo{}, err
+	}
+	t := &tab{id: chromedp.FromContext(tabCtx).Target.TargetID, ctx: tabCtx, cancel: cancel}
+	c.tabs = append(c.tabs, t)
+	c.active = t
+	c.initTabLocked(t)
+
+	info := TabInfo{ID: string(t.id), URL: "about:blank", Active: true}
+	if url != "" {
+		runCtx, runCancel := context.WithTimeout(tabCtx, 30*time.Second)
+		defer runCancel()
+		err := chromedp.Run(runCtx,
+			chromedp.Navigate(url),
+			chromedp.WaitReady("body", chromedp.ByQuery),
+			chromedp.Location(&info.URL),
+			chromedp.Title(&info.Title),
+		)
+		if err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// SwitchTab makes the given tab active. Tabs the controller hasn't seen yet
+// (opened by the page) are attached on demand.
+func (c *Controller) SwitchTab(ctx context.Context, id string) (TabInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.findTabLocked(id)
+	if err != nil {
+		t, err = c.adoptTabLocked(ctx, id)
+		if err != nil {
+			return TabInfo{}, err
+		}
+	}
+	c.active = t
+
+	cc := chromedp.FromContext(c.conn)
+	if cc != nil && cc.Browser != nil {
+		_ = target.ActivateTarget(t.id).Do(cdp.WithExecutor(ctx, cc.Browser))
+	}
+
+	info := TabInfo{ID: string(t.id), Active: true}
+	runCtx, cancel := context.WithTimeout(t.ctx, 5*time.Second)
+	defer cancel()
+	_ = chromedp.Run(runCtx, chromedp.Location(&info.URL), chromedp.Title(&info.Title))
+	return info, nil
+}
+
+func (c *Controller) adoptTabLocked(ctx context.Context, id string) (*tab, error) {
+	infos, err := c.pageTargetsLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var match target.ID
+	for _, ti := range infos {
+		if strings.HasPrefix(string(ti.TargetID), id) {
+			if match != "" {
+				return nil, fmt.Errorf("ambiguous tab id %q", id)
+			}
+			match = ti.TargetID
+		}
+	}
+	if match == "" {
+		return nil, fmt.Errorf("unknown tab %q", id)
+	}
+
+	tabCtx, cancel := chromedp.NewContext(context.WithoutCancel(c.conn), chromedp.WithTargetID(match))
+	if err := chromedp.Run(tabCtx); err != nil {
+		cancel()
+		return nil, err
+	}
+	t := &tab{id: match, ctx: tabCtx, cancel: cancel}
+	c.tabs = append(c.tabs, t)
+	c.initTabLocked(t)
+	return t, nil
+}
+
+// CloseTab closes the given tab (the active one if id is empty). Closing the
+// active tab activates the next remaining tab; the last tab can't be closed.
+func (c *Controller) CloseTab(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.active
+	if id != "" {
+		var err error
+		if t, err = c.findTabLocked(id); err != nil {
+			return err
+		}
+	}
+	if t == nil {
+		return errors.New("no active tab")
+	}
+	if len(c.tabs) <= 1 {
+		return errors.New("cannot close the last tab")
+	}
+
+	if t.cancel != nil {
+		t.cancel()
+	} else if cc := chromedp.FromContext(c.conn); cc != nil && cc.Browser != nil {
+		// Keep the connection-owning context alive; just close its target.
+		if err := target.CloseTarget(t.id).Do(cdp.WithExecutor(ctx, cc.Browser)); err != nil {
+			return err
+		}
+	}
+
+	c.removeTabLocked(t)
+	return nil
+}
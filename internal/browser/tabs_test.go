@@ -0,0 +1,61 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chromedp/cdproto/target"
+)
+
+func TestFindTabLocked_PrefixMatching(t *testing.T) {
+	c := &Controller{tabs: []*tab{{id: "ABC111"}, {id: "ABC222"}, {id: "DEF333"}}}
+
+	if got, err := c.findTabLocked("DEF"); err != nil || got.id != "DEF333" {
+		t.Fatalf("prefix: got %v err=%v", got, err)
+	}
+	if got, err := c.findTabLocked("ABC222"); err != nil || got.id != "ABC222" {
+		t.Fatalf("exact: got %v err=%v", got, err)
+	}
+	if _, err := c.findTabLocked("ABC"); err == nil {
+		t.Fatal("expected ambiguous prefix error")
+	}
+	if _, err := c.findTabLocked("XYZ"); err == nil {
+		t.Fatal("expected unknown tab error")
+	}
+}
+
+func TestWithTab(t *testing.T) {
+	ctx := context.Background()
+	if WithTab(ctx, "") != ctx {
+		t.Fatal("empty tab should return the parent context")
+	}
+	if got := tabFromContext(WithTab(ctx, "ABC")); got != "ABC" {
+		t.Fatalf("tab = %q", got)
+	}
+}
+
+func TestDropCrashedTab(t *testing.T) {
+	closed := map[string]bool{}
+	newTab := func(id string) *tab {
+		return &tab{id: target.ID(id), cancel: func() { closed[id] = true }}
+	}
+	root, a, b := &tab{id: "ROOT"}, newTab("A"), newTab("B")
+	c := &Controller{tabs: []*tab{root, a, b}, active: b}
+	browserCrashed := false
+	onCrash := func() { browserCrashed = true }
+
+	c.dropCrashedTab(b, onCrash)
+	if browserCrashed || !closed["B"] || len(c.tabs) != 2 || c.active != a {
+		t.Fatalf("after B: crashed=%v closed=%v tabs=%d active=%v", browserCrashed, closed, len(c.tabs), c.active.id)
+	}
+	c.dropCrashedTab(b, onCrash) // already gone
+	if len(c.tabs) != 2 {
+		t.Fatalf("tabs = %d", len(c.tabs))
+	}
+
+	c.tabs, c.active = []*tab{a}, a
+	c.dropCrashedTab(a, onCrash)
+	if !browserCrashed || closed["A"] {
+		t.Fatalf("last tab: crashed=%v closed=%v", browserCrashed, closed)
+	}
+}
@@ -0,0 +1,81 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// NetworkConditions is a network throttling profile applied via
+// Network.emulateNetworkConditions. Zero throughput means unthrottled.
+type NetworkConditions struct {
+	Preset       string // preset name, if any
+	Offline      bool
+	LatencyMs    float64
+	DownloadKbps float64
+	UploadKbps   float64
+}
+
+// Throughput/latency values match the Chrome DevTools throttling presets.
+var networkPresets = []NetworkConditions{
+	{Preset: "slow-3g", LatencyMs: 2000, DownloadKbps: 400, UploadKbps: 400},
+	{Preset: "fast-3g", LatencyMs: 562.5, DownloadKbps: 1440, UploadKbps: 675},
+	{Preset: "offline", Offline: true},
+}
+
+// NetworkPresets returns the built-in throttling presets.
+func NetworkPresets() []NetworkConditions {
+	out := make([]NetworkConditions, len(networkPresets))
+	copy(out, networkPresets)
+	return out
+}
+
+// LookupNetworkPreset finds a throttling preset by name (case-insensitive).
+func LookupNetworkPreset(name string) (NetworkConditions, bool) {
+	for _, p := range networkPresets {
+		if strings.EqualFold(p.Preset, name) {
+			return p, true
+		}
+	}
+	return NetworkConditions{}, false
+}
+
+// SetNetworkConditions throttles (or takes offline) every tab; nil restores
+// the unthrottled network.
+func (c *Controller) SetNetworkConditions(ctx context.Context, n *NetworkConditions) error {
+	if n != nil && (n.LatencyMs < 0 || n.DownloadKbps < 0 || n.UploadKbps < 0) {
+		return errors.New("latency and throughput must not be negative")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.emulation.Network
+	err := c.applyToTabsLocked(
+		func(ctx context.Context) error { return applyNetworkConditions(ctx, n, prev != nil) },
+		func(ctx context.Context) error { return applyNetworkConditions(ctx, prev, true) })
+	if err != nil {
+		return err
+	}
+	c.emulation.Network = n
+	return nil
+}
+
+func applyNetworkConditions(tabCtx context.Context, n *NetworkConditions, clear bool) error {
+	if n == nil {
+		if !clear {
+			return nil
+		}
+		return network.EmulateNetworkConditions(false, 0, -1, -1).Do(tabCtx)
+	}
+	return network.EmulateNetworkConditions(n.Offline, n.LatencyMs, kbpsToBytes(n.DownloadKbps), kbpsToBytes(n.UploadKbps)).Do(tabCtx)
+}
+
+// kbpsToBytes converts kbit/s to the bytes/s CDP expects; 0 disables throttling.
+func kbpsToBytes(kbps float64) float64 {
+	if kbps <= 0 {
+		return -1
+	}
+	return kbps * 1000 / 8
+}
@@ -0,0 +1,140 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	cdpio "github.com/chromedp/cdproto/io"
+	"github.com/chromedp/cdproto/tracing"
+)
+
+// DefaultTraceCategories are the categories of the DevTools performance
+// panel. A leading "-" excludes a category.
+var DefaultTraceCategories = []string{
+	"-*",
+	"devtools.timeline",
+	"disabled-by-default-devtools.timeline",
+	"disabled-by-default-devtools.timeline.frame",
+	"disabled-by-default-devtools.timeline.stack",
+	"disabled-by-default-devtools.screenshot",
+	"v8.execute",
+	"disabled-by-default-v8.cpu_profiler",
+	"toplevel",
+	"blink.console",
+	"blink.user_timing",
+	"latencyInfo",
+}
+
+// traceSession is the running trace (at most one per controller).
+type traceSession struct {
+	tabID string
+}
+
+func traceConfig(categories []string) *tracing.TraceConfig {
+	cfg := &tracing.TraceConfig{}
+	for _, cat := range categories {
+		if ex, ok := strings.CutPrefix(cat, "-"); ok {
+			cfg.ExcludedCategories = append(cfg.ExcludedCategories, ex)
+			continue
+		}
+		cfg.IncludedCategories = append(cfg.IncludedCategories, cat)
+	}
+	return cfg
+}
+
+// StartTrace starts a Chrome trace of the tab (default categories when
+// categories is empty).
+func (c *Controller) StartTrace(ctx context.Context, categories []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.trace != nil {
+		return fmt.Errorf("a trace of tab %s is already running", c.trace.tabID)
+	}
+	t, err := c.tabLocked(ctx)
+	if err != nil {
+		return err
+	}
+	if len(categories) == 0 {
+		categories = DefaultTraceCategories
+	}
+	if err := runOnTab(t.ctx, tracing.Start().
+		WithTraceConfig(traceConfig(categories)).
+		WithTransferMode(tracing.TransferModeReturnAsStream).
+		WithStreamFormat(tracing.StreamFormatJSON).
+		Do); err != nil {
+		return err
+	}
+	c.trace = &traceSession{tabID: string(t.id)}
+	return nil
+}
+
+// Tracing reports the tab of the running trace, if any.
+func (c *Controller) Tracing() (tabID string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.trace == nil {
+		return "", false
+	}
+	return c.trace.tabID, true
+}
+
+// StopTrace ends the running trace and streams it to w as trace event JSON
+// (loadable in Perfetto and chrome://tracing).
+func (c *Controller) StopTrace(ctx context.Context, w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.trace == nil {
+		return errors.New("no trace running")
+	}
+	tabID := c.trace.tabID
+	t, err := c.findTabLocked(tabID)
+	if err != nil {
+		c.trace = nil
+		return fmt.Errorf("traced tab is gone: %w", err)
+	}
+
+	done := make(chan *tracing.EventTracingComplete, 1)
+	unsubscribe := c.Subscribe(func(id string, ev any) {
+		if e, ok := ev.(*tracing.EventTracingComplete); ok && id == tabID {
+			select {
+			case done <- e:
+			default:
+			}
+		}
+	})
+	defer unsubscribe()
+
+	// If End fails, the trace keeps running and a later stop can retry.
+	if err := runOnTab(t.ctx, tracing.End().Do); err != nil {
+		return err
+	}
+	c.trace = nil
+	var complete *tracing.EventTracingComplete
+	select {
+	case complete = <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if complete.Stream == "" {
+		return errors.New("trace finished without data")
+	}
+
+	return runOnTab(t.ctx, func(ctx context.Context) error {
+		defer func() { _ = cdpio.Close(complete.Stream).Do(ctx) }()
+		for {
+			data, eof, err := cdpio.Read(complete.Stream).WithSize(1 << 20).Do(ctx)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, data); err != nil {
+				return err
+			}
+			if eof {
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTraceConfig(t *testing.T) {
+	cfg := traceConfig([]string{"-*", "devtools.timeline", "-v8", "toplevel"})
+	if !reflect.DeepEqual(cfg.IncludedCategories, []string{"devtools.timeline", "toplevel"}) {
+		t.Fatalf("included = %v", cfg.IncludedCategories)
+	}
+	if !reflect.DeepEqual(cfg.ExcludedCategories, []string{"*", "v8"}) {
+		t.Fatalf("excluded = %v", cfg.ExcludedCategories)
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newA11yCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "a11y",
+		Short: "Inspect the page as assistive technology sees it",
+	}
+	cmd.AddCommand(newA11yTreeCmd(root), newA11yAuditCmd(root))
+	return cmd
+}
+
+func newA11yTreeCmd(root *rootFlags) *cobra.Command {
+	var req rpc.A11yTreeRequest
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Print the accessibility tree",
+		Long: `Print the accessibility tree (Accessibility.getFullAXTree): one node per line
+with its role, name, value and states, indented by nesting. Nodes Chrome
+ignores are left out.
+
+--interesting-only also drops generic containers and unnamed structure,
+keeping widgets, landmarks, headings, images and text: a compact outline of
+the page.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			out, err := c.WithTab(root.tab).A11yTree(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out.Nodes)
+			}
+			printA11yTree(os.Stdout, out.Nodes, 0)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&req.Selector, "selector", "", "Only the subtree of this element")
+	cmd.Flags().BoolVar(&req.InterestingOnly, "interesting-only", false, "Drop generic containers and unnamed structure")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+// printA11yTree writes one line per node (role, quoted name, value,
+// description, states), indented two spaces per level.
+func printA11yTree(w io.Writer, nodes []rpc.A11yNode, depth int) {
+	for _, n := range nodes {
+		var b strings.Builder
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString("- ")
+		b.WriteString(n.Role)
+		if n.Name != "" {
+			fmt.Fprintf(&b, " %q", n.Name)
+		}
+		if n.Value != "" {
+			fmt.Fprintf(&b, " value=%q", n.Value)
+		}
+		if n.Description != "" {
+			fmt.Fprintf(&b, " description=%q", n.Description)
+		}
+		for _, s := range n.States {
+			b.WriteString(" ")
+			b.WriteString(s)
+		}
+		fmt.Fprintln(w, b.String())
+		printA11yTree(w, n.Children, depth+1)
+	}
+}
+
+func newA11yAuditCmd(root *rootFlags) *cobra.Command {
+	var failOn string
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check the page for accessibility problems",
+		Long: `Check the page against a built-in rule set, offline, using the live DOM,
+computed styles and the accessibility tree:
+
+  image-alt       images without alt text (critical)
+  label           form controls without a label (critical)
+  color-contrast  text below 4.5:1 contrast, 3:1 for large text (serious)
+  focusable-name  focusable elements without an accessible name (serious)
+  html-lang       no lang attribute on <html> (serious)
+  heading-order   heading levels that skip one, e.g. h2 to h4 (moderate)
+  duplicate-id    IDs used more than once (serious if a label or ARIA
+                  attribute refers to them, else minor)
+
+Exits non-zero if a violation is at least as severe as --fail-on.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			threshold := -1 // --fail-on none
+			if failOn != "none" {
+				threshold = slices.Index(browser.A11ySeverities, failOn)
+				if threshold < 0 {
+					return fmt.Errorf("invalid --fail-on %q (want %s or none)", failOn, strings.Join(browser.A11ySeverities, ", "))
+				}
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			out, err := c.WithTab(root.tab).WithTimeout(time.Minute).A11yAudit(ctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				if err := printJSON(out); err != nil {
+					return err
+				}
+			} else {
+				printA11yAudit(os.Stdout, out.Violations)
+			}
+
+			failing := 0
+			for _, v := range out.Violations {
+				if i := slices.Index(browser.A11ySeverities, v.Severity); i >= 0 && i <= threshold {
+					failing++
+				}
+			}
+			if failing > 0 {
+				return fmt.Errorf("%d accessibility violations at or above %s", failing, failOn)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&failOn, "fail-on", "serious", "Exit non-zero for violations this severe or worse: critical, serious, moderate, minor or none")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+// printA11yAudit writes each violation as a line of severity, rule and
+// selector, followed by an indented line with the message and the element.
+func printA11yAudit(w io.Writer, violations []rpc.A11yViolation) {
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "no violations found")
+		return
+	}
+	counts := map[string]int{}
+	for _, v := range violations {
+		counts[v.Severity]++
+		selector := v.Selector
+		if selector == "" {
+			selector = "(element not located)"
+		}
+		fmt.Fprintf(w, "%-8s  %-14s  %s\n", v.Severity, v.Rule, selector)
+		if v.HTML != "" {
+			fmt.Fprintf(w, "          %s: %s\n", v.Message, v.HTML)
+		} else {
+			fmt.Fprintf(w, "          %s\n", v.Message)
+		}
+	}
+	var parts []string
+	for _, s := range browser.A11ySeverities {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+	noun := "violations"
+	if len(violations) == 1 {
+		noun = "violation"
+	}
+	fmt.Fprintf(w, "\n%d %s: %s\n", len(violations), noun, strings.Join(parts, ", "))
+}
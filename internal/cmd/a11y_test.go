@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestA11yTreeCommand(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var got rpc.A11yTreeRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/a11y/tree", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(rpc.A11yTreeResponse{Nodes: []rpc.A11yNode{{
+				Role: "form",
+				Children: []rpc.A11yNode{
+					{Role: "textbox", Name: "Email", Value: "a@b.c", States: []string{"focusable", "required"}},
+					{Role: "button", Name: "Send", Description: "Sends \"now\""},
+				},
+			}}})
+		})
+	})
+	t.Cleanup(shutdown)
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newA11yCmd(&rootFlags{})
+	cmd.SetArgs([]string{"tree", "--selector", "form", "--interesting-only"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != (rpc.A11yTreeRequest{Selector: "form", InterestingOnly: true}) {
+		t.Fatalf("request = %+v", got)
+	}
+	want := `- form
+  - textbox "Email" value="a@b.c" focusable required
+  - button "Send" description="Sends \"now\""
+`
+	if buf.String() != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func runA11yAudit(t *testing.T, violations []rpc.A11yViolation, args ...string) (string, error) {
+	t.Helper()
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/a11y/audit", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(rpc.A11yAuditResponse{Violations: violations})
+		})
+	})
+	t.Cleanup(shutdown)
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newA11yCmd(&rootFlags{})
+	cmd.SetArgs(append([]string{"audit"}, args...))
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	return buf.String(), err
+}
+
+func TestA11yAuditCommand(t *testing.T) {
+	violations := []rpc.A11yViolation{
+		{Rule: "color-contrast", Severity: "serious", Selector: "main > p", Message: "Contrast 2.85:1 (#999999 on #ffffff), needs 4.5:1", HTML: "<p>"},
+		{Rule: "heading-order", Severity: "moderate", Selector: "#faq", Message: "Heading level 4 follows level 2"},
+		{Rule: "focusable-name", Severity: "minor", Message: "Focusable generic has no accessible name"},
+	}
+
+	out, err := runA11yAudit(t, violations)
+	if err == nil || !strings.Contains(err.Error(), "1 accessibility violations at or above serious") {
+		t.Fatalf("error = %v", err)
+	}
+	want := `serious   color-contrast  main > p
+          Contrast 2.85:1 (#999999 on #ffffff), needs 4.5:1: <p>
+moderate  heading-order   #faq
+          Heading level 4 follows level 2
+minor     focusable-name  (element not located)
+          Focusable generic has no accessible name
+
+3 violations: 1 serious, 1 moderate, 1 minor
+`
+	if out != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", out, want)
+	}
+
+	if _, err := runA11yAudit(t, violations, "--fail-on", "critical"); err != nil {
+		t.Fatalf("--fail-on critical: %v", err)
+	}
+	if _, err := runA11yAudit(t, violations, "--fail-on", "none"); err != nil {
+		t.Fatalf("--fail-on none: %v", err)
+	}
+	if _, err := runA11yAudit(t, violations, "--fail-on", "minor"); err == nil || !strings.Contains(err.Error(), "3 accessibility") {
+		t.Fatalf("--fail-on minor: %v", err)
+	}
+	if _, err := runA11yAudit(t, violations, "--fail-on", "bad"); err == nil {
+		t.Fatal("expected an invalid --fail-on error")
+	}
+	if out, err := runA11yAudit(t, nil); err != nil || out != "no violations found\n" {
+		t.Fatalf("clean page = %q, %v", out, err)
+	}
+}
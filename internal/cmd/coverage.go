@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newCoverageCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "JavaScript and CSS coverage of the page",
+		Long: `Record which JavaScript and CSS the page used (Profiler block coverage and CSS
+rule usage), mapped back to files under the serve dir.
+
+Coverage starts with the scripts and styles already loaded; to cover the page
+load, reload after starting:
+
+  canvas coverage start && canvas reload
+  # … use the page …
+  canvas coverage stop --lcov coverage.info`,
+	}
+	cmd.AddCommand(newCoverageStartCmd(root), newCoverageStopCmd(root))
+	return cmd
+}
+
+func newCoverageStartCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start recording coverage of the tab",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			out, err := c.WithTab(root.tab).CoverageStart(ctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			fmt.Fprintf(os.Stdout, "recording coverage of tab %s (stop with `canvas coverage stop`)\n", out.Tab)
+			return nil
+		},
+	}
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func newCoverageStopCmd(root *rootFlags) *cobra.Command {
+	var (
+		outPath  string
+		lcovPath string
+	)
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop recording and report coverage per file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			rep, err := c.WithTimeout(2 * time.Minute).CoverageStop(ctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if outPath != "" {
+				data, err := json.MarshalIndent(rep, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(outPath, append(data, '\n'), 0o644); err != nil {
+					return err
+				}
+			}
+			if lcovPath != "" {
+				if _, err := writeFileFrom(lcovPath, func(w io.Writer) error { return writeLcov(w, rep) }); err != nil {
+					return err
+				}
+			}
+			if root.jsonOutput {
+				return printJSON(rep)
+			}
+			printCoverage(os.Stdout, rep)
+			for _, p := range []string{outPath, lcovPath} {
+				if p != "" {
+					fmt.Fprintf(os.Stdout, "wrote %s\n", p)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the JSON report to this file")
+	cmd.Flags().StringVar(&lcovPath, "lcov", "", "Write line coverage in lcov format to this file")
+	return cmd
+}
+
+func printCoverage(w io.Writer, rep rpc.CoverageReport) {
+	if len(rep.Files) == 0 {
+		fmt.Fprintln(w, "no scripts or style sheets covered")
+		return
+	}
+	fmt.Fprintf(w, "%-4s %7s %9s %9s  %s\n", "type", "used%", "used", "total", "file")
+	for _, f := range rep.Files {
+		fmt.Fprintf(w, "%-4s %6.1f%% %9d %9d  %s\n", f.Type, f.Percent, f.Used, f.Total, coverageName(f))
+	}
+	fmt.Fprintf(w, "%-4s %6.1f%% %9d %9d\n", "all", rep.Percent, rep.Used, rep.Total)
+}
+
+func coverageName(f rpc.CoverageFile) string {
+	if f.Path != "" {
+		return f.Path
+	}
+	return f.URL
+}
+
+// writeLcov writes one lcov record per file (JS and CSS of a page merged),
+// with absolute paths for files under the serve dir and URLs otherwise.
+func writeLcov(w io.Writer, rep rpc.CoverageReport) error {
+	var order []string
+	lines := map[string]map[int]bool{}
+	for _, f := range rep.Files {
+		name := f.URL
+		if f.Path != "" {
+			name = filepath.Join(rep.Root, filepath.FromSlash(f.Path))
+		}
+		if lines[name] == nil {
+			lines[name] = map[int]bool{}
+			order = append(order, name)
+		}
+		for _, l := range f.Uncovered {
+			if _, ok := lines[name][l]; !ok {
+				lines[name][l] = false
+			}
+		}
+		for _, l := range f.Covered {
+			lines[name][l] = true
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, name := range order {
+		nums := make([]int, 0, len(lines[name]))
+		for l := range lines[name] {
+			nums = append(nums, l)
+		}
+		slices.Sort(nums)
+		hit := 0
+		fmt.Fprintf(bw, "TN:\nSF:%s\n", name)
+		for _, l := range nums {
+			n := 0
+			if lines[name][l] {
+				n = 1
+				hit++
+			}
+			fmt.Fprintf(bw, "DA:%d,%d\n", l, n)
+		}
+		fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", len(nums), hit)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+var testCoverageReport = rpc.CoverageReport{
+	Root: "/srv", Total: 300, Used: 150, Percent: 50,
+	Files: []rpc.CoverageFile{
+		{URL: "http://127.0.0.1:1/", Path: "index.html", Type: "css", Total: 40, Used: 30, Percent: 75, Covered: []int{3}, Uncovered: []int{4}},
+		{URL: "http://127.0.0.1:1/", Path: "index.html", Type: "js", Total: 60, Used: 20, Percent: 33.3333, Covered: []int{9}, Uncovered: []int{4, 10}},
+		{URL: "https://cdn.example.com/lib.js", Type: "js", Total: 200, Used: 100, Percent: 50, Covered: []int{1}},
+	},
+}
+
+func TestPrintCoverage(t *testing.T) {
+	var buf bytes.Buffer
+	printCoverage(&buf, testCoverageReport)
+	want := "type   used%      used     total  file\n" +
+		"css    75.0%        30        40  index.html\n" +
+		"js     33.3%        20        60  index.html\n" +
+		"js     50.0%       100       200  https://cdn.example.com/lib.js\n" +
+		"all    50.0%       150       300\n"
+	if buf.String() != want {
+		t.Fatalf("report:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteLcov(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeLcov(&buf, testCoverageReport); err != nil {
+		t.Fatal(err)
+	}
+	// Line 4 has unused CSS and JS; line 3 is used CSS.
+	want := "TN:\nSF:" + filepath.Join("/srv", "index.html") + "\n" +
+		"DA:3,1\nDA:4,0\nDA:9,1\nDA:10,0\nLF:4\nLH:2\nend_of_record\n" +
+		"TN:\nSF:https://cdn.example.com/lib.js\nDA:1,1\nLF:1\nLH:1\nend_of_record\n"
+	if buf.String() != want {
+		t.Fatalf("lcov:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestCoverageStopWritesReports(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/coverage/stop", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(testCoverageReport)
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	jsonPath, lcovPath := filepath.Join(dir, "coverage.json"), filepath.Join(dir, "coverage.info")
+	cmd := newCoverageCmd(&rootFlags{})
+	cmd.SetArgs([]string{"stop", "-o", jsonPath, "--lcov", lcovPath})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(jsonPath); err != nil || !bytes.Contains(data, []byte(`"path": "index.html"`)) {
+		t.Fatalf("json = %s, %v", data, err)
+	}
+	if data, err := os.ReadFile(lcovPath); err != nil || !bytes.HasPrefix(data, []byte("TN:\nSF:")) {
+		t.Fatalf("lcov = %s, %v", data, err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("wrote "+jsonPath+"\nwrote "+lcovPath+"\n")) {
+		t.Fatalf("output = %q", buf.String())
+	}
+}
@@ -18,6 +18,7 @@ func newDaemonCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVar(&cfg.Session, "session", "", "Session name")
 	cmd.Flags().StringVar(&cfg.StateDir, "state-dir", "", "State directory")
 	cmd.Flags().StringVar(&cfg.ServeDir, "dir", "", "Directory to serve")
 	cmd.Flags().IntVar(&cfg.HTTPPort, "port", 0, "HTTP port")
@@ -27,6 +28,9 @@ func newDaemonCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&cfg.Stealth, "stealth", true, "Best-effort automation detection reduction")
 	cmd.Flags().StringVar(&cfg.WindowSize, "window-size", "1280,720", "Browser window size, e.g. 1280,720")
 	cmd.Flags().StringVar(&cfg.BrowserBin, "browser-bin", "", "Chromium/Chrome binary path (optional)")
+	cmd.Flags().StringVar(&cfg.Attach, "attach", "", "Attach to a running browser (DevTools ws URL or port)")
+	cmd.Flags().BoolVar(&cfg.AdoptTab, "adopt-tab", false, "With --attach, reuse the browser's first tab instead of opening one")
+	cmd.Flags().StringVar(&cfg.RoutesFile, "routes", "", "Load mock routes from a JSON file")
 	cmd.Flags().BoolVar(&cfg.TempDir, "temp-dir", false, "Remove served directory on shutdown")
 
 	_ = cmd.MarkFlagRequired("state-dir")
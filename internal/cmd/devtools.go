@@ -14,7 +14,7 @@ func newDevToolsCmd(root *rootFlags) *cobra.Command {
 		Use:   "devtools",
 		Short: "Print DevTools debugging port / websocket URL for the controlled browser",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
@@ -27,6 +27,7 @@ func newDomCmd(root *rootFlags) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&mode, "mode", "outer_html", "Query mode: outer_html or text")
+	cmd.PersistentFlags().StringVar(&root.tab, "tab", "", "Target a specific tab (ID or unique prefix) without switching")
 
 	cmd.AddCommand(
 		newDomQueryCmd(root, &mode),
@@ -52,10 +53,11 @@ func newDomQueryCmd(root *rootFlags, mode *string) *cobra.Command {
 }
 
 func runDomQuery(root *rootFlags, selector, mode string) error {
-	c, _, _, err := mustClient()
+	c, _, _, err := mustClient(root)
 	if err != nil {
 		return err
 	}
+	c = c.WithTab(root.tab)
 	if mode == "" {
 		mode = "outer_html"
 	}
@@ -80,10 +82,11 @@ func newDomAllCmd(root *rootFlags, mode *string) *cobra.Command {
 		Short: "Query all matching elements",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			m := *mode
 			if m == "" {
 				m = "outer_html"
@@ -127,10 +130,11 @@ func newDomAttrCmd(root *rootFlags) *cobra.Command {
 		Short: "Get an attribute value from the first matching element",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			out, err := c.DomAttr(ctx, args[0], args[1])
 			cancel()
@@ -155,10 +159,11 @@ func newDomClickCmd(root *rootFlags) *cobra.Command {
 		Short: "Click the first matching element",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			out, err := c.DomClick(ctx, args[0])
 			cancel()
@@ -185,10 +190,11 @@ func newDomTypeCmd(root *rootFlags) *cobra.Command {
 		Short: "Type into the first matching element",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			out, err := c.DomType(ctx, args[0], args[1], clear)
 			cancel()
@@ -221,10 +227,11 @@ func newDomWaitCmd(root *rootFlags) *cobra.Command {
 		Short: "Wait for a selector state (visible by default)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			if state == "" {
 				state = "visible"
 			}
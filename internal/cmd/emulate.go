@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newEmulateCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "emulate",
+		Short: "Emulate devices and browser conditions",
+	}
+	cmd.AddCommand(
+		newEmulateDeviceCmd(root),
+		newEmulateMediaCmd(root),
+		newEmulateLocaleCmd(root),
+		newEmulateTimezoneCmd(root),
+		newEmulateGeolocationCmd(root),
+		newEmulateClearCmd(root),
+	)
+	return cmd
+}
+
+func newEmulateDeviceCmd(root *rootFlags) *cobra.Command {
+	var (
+		list bool
+		req  rpc.EmulateDeviceRequest
+	)
+	var mobile, touch bool
+
+	cmd := &cobra.Command{
+		Use:   "device [name]",
+		Short: "Emulate a device preset or a custom viewport",
+		Long: `Emulate a device preset (e.g. "iPhone 15", "Pixel 8", "iPad Air") or a custom
+viewport. Explicit flags override the preset. The emulation applies to all tabs
+and survives reloads, new tabs and browser restarts until cleared.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				return printDevices(root)
+			}
+			if len(args) == 1 {
+				req.Device = args[0]
+			}
+			if req.Device == "" && (req.Width == 0 || req.Height == 0) {
+				return fmt.Errorf("missing device name or --width/--height (see --list)")
+			}
+			if cmd.Flags().Changed("mobile") {
+				req.Mobile = &mobile
+			}
+			if cmd.Flags().Changed("touch") {
+				req.Touch = &touch
+			}
+
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.EmulateDevice(ctx, req)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List device presets")
+	cmd.Flags().Int64Var(&req.Width, "width", 0, "Viewport width in CSS pixels")
+	cmd.Flags().Int64Var(&req.Height, "height", 0, "Viewport height in CSS pixels")
+	cmd.Flags().Float64Var(&req.DeviceScaleFactor, "dpr", 0, "Device pixel ratio")
+	cmd.Flags().BoolVar(&mobile, "mobile", false, "Emulate a mobile device (meta viewport, overlay scrollbars)")
+	cmd.Flags().BoolVar(&touch, "touch", false, "Emulate touch input")
+	cmd.Flags().StringVar(&req.UserAgent, "user-agent", "", "Override the user agent")
+	cmd.Flags().BoolVar(&req.Landscape, "landscape", false, "Rotate the viewport to landscape")
+	return cmd
+}
+
+func newEmulateMediaCmd(root *rootFlags) *cobra.Command {
+	var req rpc.EmulateMediaRequest
+
+	cmd := &cobra.Command{
+		Use:   "media",
+		Short: "Emulate CSS media type and features (dark mode, reduced motion, print)",
+		Long: `Emulate CSS media type and features. Flags merge into the current override;
+pass "default" to reset a single feature. The override survives reloads.`,
+		Example: `  canvas emulate media --color-scheme dark --reduced-motion reduce
+  canvas emulate media --type print
+  canvas emulate media --type default`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if req.Media == (rpc.Media{}) {
+				return fmt.Errorf("nothing to emulate (set --type, --color-scheme, --reduced-motion, --forced-colors or --contrast)")
+			}
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.EmulateMedia(ctx, req)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Type, "type", "", "Media type: screen|print")
+	cmd.Flags().StringVar(&req.ColorScheme, "color-scheme", "", "prefers-color-scheme: light|dark|no-preference")
+	cmd.Flags().StringVar(&req.ReducedMotion, "reduced-motion", "", "prefers-reduced-motion: reduce|no-preference")
+	cmd.Flags().StringVar(&req.ForcedColors, "forced-colors", "", "forced-colors: active|none")
+	cmd.Flags().StringVar(&req.Contrast, "contrast", "", "prefers-contrast: more|less|no-preference|custom")
+	return cmd
+}
+
+func newEmulateLocaleCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "locale <bcp47>",
+		Short:   "Emulate a locale (Intl formatting, navigator.language, Accept-Language)",
+		Example: "  canvas emulate locale de-DE",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.EmulateLocale(ctx, args[0])
+			})
+		},
+	}
+}
+
+func newEmulateTimezoneCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "timezone <iana-id>",
+		Short:   "Emulate a timezone",
+		Example: "  canvas emulate timezone Asia/Tokyo",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.EmulateTimezone(ctx, args[0])
+			})
+		},
+	}
+}
+
+func newEmulateGeolocationCmd(root *rootFlags) *cobra.Command {
+	var accuracy float64
+	cmd := &cobra.Command{
+		Use:   "geolocation <lat> <lon>",
+		Short: "Emulate a geolocation (also grants the permission)",
+		Example: `  canvas emulate geolocation 52.52 13.405
+  canvas emulate geolocation 40.7128,-74.006 --accuracy 50
+  canvas emulate geolocation -- -33.8688 151.2093   # negative latitude`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, err := parseGeolocation(args)
+			if err != nil {
+				return err
+			}
+			g.Accuracy = accuracy
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.EmulateGeolocation(ctx, g)
+			})
+		},
+	}
+	cmd.Flags().Float64Var(&accuracy, "accuracy", 0, "Accuracy in meters")
+	return cmd
+}
+
+// parseGeolocation accepts "<lat> <lon>" or "<lat>,<lon>".
+func parseGeolocation(args []string) (rpc.Geolocation, error) {
+	var g rpc.Geolocation
+	if len(args) == 1 {
+		lat, lon, ok := strings.Cut(args[0], ",")
+		if !ok {
+			return g, fmt.Errorf("expected <lat> <lon> or <lat>,<lon>")
+		}
+		args = []string{lat, lon}
+	}
+	var err error
+	if g.Latitude, err = strconv.ParseFloat(strings.TrimSpace(args[0]), 64); err != nil {
+		return g, fmt.Errorf("invalid latitude %q", args[0])
+	}
+	if g.Longitude, err = strconv.ParseFloat(strings.TrimSpace(args[1]), 64); err != nil {
+		return g, fmt.Errorf("invalid longitude %q", args[1])
+	}
+	return g, nil
+}
+
+func runEmulate(root *rootFlags, call func(context.Context, *rpc.Client) (rpc.EmulationResponse, error)) error {
+	c, _, _, err := mustClient(root)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	out, err := call(ctx, c)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if root.jsonOutput {
+		return printJSON(out)
+	}
+	printEmulation(out.Emulation)
+	return nil
+}
+
+func newEmulateClearCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear [device|media|locale|timezone|geolocation|network|all]",
+		Short: "Clear emulation overrides (default: all)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			what := "all"
+			if len(args) == 1 {
+				what = args[0]
+			}
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.EmulateClear(ctx, what)
+			})
+		},
+	}
+}
+
+func printDevices(root *rootFlags) error {
+	devices := browser.Devices()
+	if root.jsonOutput {
+		return printJSON(devices)
+	}
+	for _, d := range devices {
+		kind := "desktop"
+		if d.Mobile {
+			kind = "mobile"
+		}
+		fmt.Fprintf(os.Stdout, "%-18s %5dx%-5d @%gx  %s\n", d.Device, d.Width, d.Height, d.DeviceScaleFactor, kind)
+	}
+	return nil
+}
+
+func printEmulation(e rpc.Emulation) {
+	lines := emulationLines(e)
+	if len(lines) == 0 {
+		fmt.Fprintln(os.Stdout, "emulation: none")
+		return
+	}
+	for _, l := range lines {
+		fmt.Fprintln(os.Stdout, l)
+	}
+}
+
+func emulationLines(e rpc.Emulation) []string {
+	var lines []string
+	if v := e.Viewport; v != nil {
+		name := v.Device
+		if name == "" {
+			name = "custom"
+		}
+		var extra []string
+		if v.Mobile {
+			extra = append(extra, "mobile")
+		}
+		if v.Touch {
+			extra = append(extra, "touch")
+		}
+		line := fmt.Sprintf("device: %s %dx%d @%gx", name, v.Width, v.Height, v.DeviceScaleFactor)
+		if len(extra) > 0 {
+			line += " (" + strings.Join(extra, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	if m := e.Media; m != nil {
+		var parts []string
+		for _, f := range []struct{ name, value string }{
+			{"type", m.Type},
+			{"color-scheme", m.ColorScheme},
+			{"reduced-motion", m.ReducedMotion},
+			{"forced-colors", m.ForcedColors},
+			{"contrast", m.Contrast},
+		} {
+			if f.value != "" {
+				parts = append(parts, f.name+"="+f.value)
+			}
+		}
+		lines = append(lines, "media: "+strings.Join(parts, " "))
+	}
+	if e.Locale != "" {
+		lines = append(lines, "locale: "+e.Locale)
+	}
+	if e.Timezone != "" {
+		lines = append(lines, "timezone: "+e.Timezone)
+	}
+	if g := e.Geolocation; g != nil {
+		line := fmt.Sprintf("geolocation: %g,%g", g.Latitude, g.Longitude)
+		if g.Accuracy > 0 {
+			line += fmt.Sprintf(" (±%gm)", g.Accuracy)
+		}
+		lines = append(lines, line)
+	}
+	if n := e.Network; n != nil {
+		lines = append(lines, "network: "+networkLine(*n))
+	}
+	return lines
+}
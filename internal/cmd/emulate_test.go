@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestEmulateDeviceCommand_SendsOverrides(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var got rpc.EmulateDeviceRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/emulate/device", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(rpc.EmulationResponse{Emulation: rpc.Emulation{Viewport: &rpc.Viewport{
+				Device: "iPhone 15", Width: 852, Height: 393, DeviceScaleFactor: 3, Mobile: true,
+			}}})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newEmulateCmd(&rootFlags{})
+	cmd.SetArgs([]string{"device", "iPhone 15", "--landscape", "--touch=false"})
+
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	if got.Device != "iPhone 15" || !got.Landscape || got.Mobile != nil || got.Touch == nil || *got.Touch {
+		t.Fatalf("unexpected request: %#v", got)
+	}
+	if want := "device: iPhone 15 852x393 @3x (mobile)\n"; buf.String() != want {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestEmulateMediaCommand_Text(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var got rpc.EmulateMediaRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/emulate/media", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			m := got.Media
+			_ = json.NewEncoder(w).Encode(rpc.EmulationResponse{Emulation: rpc.Emulation{Media: &m}})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newEmulateCmd(&rootFlags{})
+	cmd.SetArgs([]string{"media", "--color-scheme", "dark", "--reduced-motion", "reduce"})
+
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	if got.ColorScheme != "dark" || got.ReducedMotion != "reduce" || got.Type != "" {
+		t.Fatalf("unexpected request: %#v", got)
+	}
+	if want := "media: color-scheme=dark reduced-motion=reduce\n"; buf.String() != want {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestParseGeolocation(t *testing.T) {
+	for _, args := range [][]string{{"52.52", "13.405"}, {"52.52,13.405"}, {"52.52, 13.405"}} {
+		g, err := parseGeolocation(args)
+		if err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+		if g.Latitude != 52.52 || g.Longitude != 13.405 {
+			t.Fatalf("%q: got %#v", args, g)
+		}
+	}
+	for _, args := range [][]string{{"52.52"}, {"north", "13"}, {"52", "east"}} {
+		if _, err := parseGeolocation(args); err == nil {
+			t.Fatalf("%q: expected error", args)
+		}
+	}
+}
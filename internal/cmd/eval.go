@@ -16,10 +16,11 @@ func newEvalCmd(root *rootFlags) *cobra.Command {
 		Short: "Evaluate JavaScript in the controlled tab",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			out, err := c.Eval(ctx, args[0])
 			cancel()
@@ -40,5 +41,6 @@ func newEvalCmd(root *rootFlags) *cobra.Command {
 			return nil
 		},
 	}
+	addTabFlag(cmd, root)
 	return cmd
 }
@@ -16,7 +16,7 @@ func newFocusCmd(root *rootFlags) *cobra.Command {
 		Use:   "focus",
 		Short: "Bring the controlled browser window to the front (macOS)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, sess, _, err := mustClient()
+			c, sess, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
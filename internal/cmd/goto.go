@@ -10,15 +10,17 @@ import (
 )
 
 func newGotoCmd(root *rootFlags) *cobra.Command {
+	var failOnError bool
 	cmd := &cobra.Command{
 		Use:   "goto <path-or-url>",
 		Short: "Navigate the controlled tab to a path (e.g. /yolo) or full URL",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			out, err := c.Goto(ctx, args[0])
 			cancel()
@@ -26,11 +28,17 @@ func newGotoCmd(root *rootFlags) *cobra.Command {
 				return err
 			}
 			if root.jsonOutput {
-				return printJSON(out)
+				if err := printJSON(out); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(os.Stdout, out.URL)
+				printLoadReport(os.Stderr, out.LoadReport)
 			}
-			fmt.Fprintln(os.Stdout, out.URL)
-			return nil
+			return checkLoadReport(out.LoadReport, failOnError)
 		},
 	}
+	addTabFlag(cmd, root)
+	addFailOnErrorFlag(cmd, &failOnError)
 	return cmd
 }
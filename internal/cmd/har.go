@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newHARCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "har",
+		Short: "Record and replay network traffic as HAR files",
+	}
+	cmd.AddCommand(newHARStartCmd(root), newHARStopCmd(root), newHARReplayCmd(root))
+	return cmd
+}
+
+func newHARStartCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "start",
+		Short: "Start recording all tabs' requests and responses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			out, err := c.HARStart(ctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			fmt.Fprintln(os.Stdout, "recording HAR (stop with `canvas har stop --out file.har`)")
+			return nil
+		},
+	}
+}
+
+func newHARStopCmd(root *rootFlags) *cobra.Command {
+	var outPath string
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop recording and write the HAR file",
+		Long: `Stop recording and write the HAR 1.2 archive (with response bodies) to --out,
+or to stdout when --out is omitted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			out, err := c.HARStop(ctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if outPath == "" {
+				_, err := os.Stdout.Write(append(out.HAR, '\n'))
+				return err
+			}
+			if err := os.WriteFile(outPath, append(out.HAR, '\n'), 0o644); err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(struct {
+					Path    string `json:"path"`
+					Entries int    `json:"entries"`
+				}{outPath, out.Entries})
+			}
+			fmt.Fprintf(os.Stdout, "wrote %d entries to %s\n", out.Entries, outPath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the HAR to this file")
+	return cmd
+}
+
+func newHARReplayCmd(root *rootFlags) *cobra.Command {
+	var req rpc.HARReplayRequest
+	cmd := &cobra.Command{
+		Use:   "replay <file.har>",
+		Short: "Answer requests from a HAR file instead of the network",
+		Long: `Answer requests from a recorded HAR file. Requests match on method and URL
+(and the post body, when several entries share a URL). Unmatched requests go to
+the network, or fail with --not-found abort. Stop replaying with --off.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case req.Off && len(args) > 0:
+				return errors.New("--off takes no file")
+			case !req.Off && len(args) == 0:
+				return errors.New("missing HAR file (or --off)")
+			}
+			switch req.NotFound {
+			case "fallback", "abort":
+			default:
+				return fmt.Errorf("--not-found must be fallback or abort, got %q", req.NotFound)
+			}
+			if !req.Off {
+				data, err := os.ReadFile(args[0])
+				if err != nil {
+					return err
+				}
+				req.HAR = data
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			out, err := c.HARReplay(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			if !out.Active {
+				fmt.Fprintln(os.Stdout, "HAR replay off")
+				return nil
+			}
+			fmt.Fprintf(os.Stdout, "replaying %d entries from %s (not found: %s)\n", out.Entries, args[0], req.NotFound)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&req.NotFound, "not-found", "fallback", "Unmatched requests: fallback (go to the network) or abort")
+	cmd.Flags().BoolVar(&req.Off, "off", false, "Stop replaying")
+	return cmd
+}
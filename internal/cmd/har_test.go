@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestHARCommands(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	const archive = `{"log":{"version":"1.2","creator":{"name":"canvas","version":"dev"},"entries":[]}}`
+	var replays []rpc.HARReplayRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/har/stop", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(rpc.HARStopResponse{Entries: 0, HAR: json.RawMessage(archive)})
+		})
+		mux.HandleFunc("/har/replay", func(w http.ResponseWriter, r *http.Request) {
+			var req rpc.HARReplayRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			replays = append(replays, req)
+			_ = json.NewEncoder(w).Encode(rpc.HARReplayResponse{Active: !req.Off, Entries: 3})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		cmd := newHARCmd(&rootFlags{})
+		cmd.SetArgs(args)
+		var buf bytes.Buffer
+		restore, err := captureStdout(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = cmd.Execute()
+		_ = restore()
+		return buf.String(), err
+	}
+
+	path := filepath.Join(t.TempDir(), "run.har")
+	if out, err := run("stop", "--out", path); err != nil || out != "wrote 0 entries to "+path+"\n" {
+		t.Fatalf("stop = %q, %v", out, err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(bytes.TrimSpace(data)) != archive {
+		t.Fatalf("har file = %q, %v", data, err)
+	}
+
+	if out, err := run("replay", path, "--not-found", "abort"); err != nil || out != "replaying 3 entries from "+path+" (not found: abort)\n" {
+		t.Fatalf("replay = %q, %v", out, err)
+	}
+	if out, err := run("replay", "--off"); err != nil || out != "HAR replay off\n" {
+		t.Fatalf("replay off = %q, %v", out, err)
+	}
+	if _, err := run("replay", path, "--not-found", "maybe"); err == nil {
+		t.Fatal("expected an error for a bad --not-found")
+	}
+	if len(replays) != 2 || replays[0].NotFound != "abort" || string(replays[0].HAR) != archive || !replays[1].Off {
+		t.Fatalf("unexpected requests: %+v", replays)
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func addFailOnErrorFlag(cmd *cobra.Command, failOnError *bool) {
+	cmd.Flags().BoolVar(failOnError, "fail-on-error", false, "Exit non-zero if the page returned an HTTP error, a request failed or the page threw/logged an error")
+}
+
+// printLoadReport lists problems observed during a page load (nothing if clean).
+func printLoadReport(w io.Writer, r rpc.LoadReport) {
+	if r.Status >= 400 {
+		fmt.Fprintf(w, "warning: document returned HTTP %d\n", r.Status)
+	}
+	for _, f := range r.FailedRequests {
+		reason := f.Error
+		if f.Status != 0 {
+			reason = fmt.Sprintf("HTTP %d", f.Status)
+		}
+		fmt.Fprintf(w, "warning: failed request: %s (%s)\n", f.URL, reason)
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(w, "warning: page error: %s", e.Text)
+		if e.URL != "" {
+			fmt.Fprintf(w, " (%s)", logLocation(e.URL, e.Line, e.Column))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func checkLoadReport(r rpc.LoadReport, failOnError bool) error {
+	if !failOnError || !r.HasErrors() {
+		return nil
+	}
+	return errors.New("page load reported errors")
+}
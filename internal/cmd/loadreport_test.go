@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestGotoCommand_FailOnError(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/goto", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(rpc.GotoResponse{URL: "http://127.0.0.1:1/", LoadReport: rpc.LoadReport{
+				Status:         200,
+				FailedRequests: []rpc.FailedRequest{{URL: "http://127.0.0.1:1/app.css", Type: "Stylesheet", Status: 404}},
+				Errors:         []rpc.LogEntry{{Level: "error", Source: "exception", Text: "Uncaught Error: boom", URL: "http://127.0.0.1:1/app.js", Line: 1, Column: 7}},
+			}})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newGotoCmd(&rootFlags{})
+	cmd.SetArgs([]string{"/"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected success without --fail-on-error: %v", err)
+	}
+
+	cmd = newGotoCmd(&rootFlags{})
+	cmd.SetArgs([]string{"/", "--fail-on-error"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error with --fail-on-error")
+	}
+}
+
+func TestPrintLoadReport(t *testing.T) {
+	var buf bytes.Buffer
+	printLoadReport(&buf, rpc.LoadReport{Status: 200})
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output for a clean load, got %q", buf.String())
+	}
+
+	printLoadReport(&buf, rpc.LoadReport{
+		Status: 404,
+		FailedRequests: []rpc.FailedRequest{
+			{URL: "http://x/app.js", Status: 500},
+			{URL: "http://cdn.invalid/lib.js", Error: "net::ERR_NAME_NOT_RESOLVED"},
+		},
+		Errors: []rpc.LogEntry{{Text: "Uncaught ReferenceError: x is not defined", URL: "http://x/app.js", Line: 2, Column: 3}},
+	})
+	want := "warning: document returned HTTP 404\n" +
+		"warning: failed request: http://x/app.js (HTTP 500)\n" +
+		"warning: failed request: http://cdn.invalid/lib.js (net::ERR_NAME_NOT_RESOLVED)\n" +
+		"warning: page error: Uncaught ReferenceError: x is not defined (http://x/app.js:2:3)\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+const logsPollInterval = 500 * time.Millisecond
+
+func newLogsCmd(root *rootFlags) *cobra.Command {
+	var (
+		follow bool
+		level  string
+		since  string
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show console messages, uncaught exceptions and browser log entries",
+		Long: `Show what the page logged (console.*), threw (uncaught exceptions) and what the
+browser reported (failed loads, CSP violations, …). The daemon keeps the most
+recent 1000 entries.`,
+		Example: `  canvas logs
+  canvas logs --level error --since 5m
+  canvas logs -f --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := rpc.LogsRequest{Limit: limit}
+			switch level {
+			case "", "debug", "info", "warning", "error":
+				req.Level = level
+			case "warn":
+				req.Level = "warning"
+			default:
+				return fmt.Errorf("invalid --level %q (want debug|info|warning|error)", level)
+			}
+			if since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				req.Since = t
+			}
+
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			out, err := c.Logs(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if !follow {
+				if root.jsonOutput {
+					return printJSON(out)
+				}
+				printLogEntries(os.Stdout, out.Entries)
+				return nil
+			}
+
+			// Follow: print entries as they arrive (JSON lines with --json).
+			emit := func(entries []rpc.LogEntry) {
+				if root.jsonOutput {
+					enc := json.NewEncoder(os.Stdout)
+					for _, e := range entries {
+						_ = enc.Encode(e)
+					}
+					return
+				}
+				printLogEntries(os.Stdout, entries)
+			}
+			emit(out.Entries)
+
+			sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			req.Limit = 0
+			req.After = out.Last
+			ticker := time.NewTicker(logsPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-sigCtx.Done():
+					return nil
+				case <-ticker.C:
+				}
+				ctx, cancel := context.WithTimeout(sigCtx, 15*time.Second)
+				out, err := c.Logs(ctx, req)
+				cancel()
+				if err != nil {
+					if sigCtx.Err() != nil {
+						return nil
+					}
+					return err
+				}
+				emit(out.Entries)
+				req.After = out.Last
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new entries")
+	cmd.Flags().StringVar(&level, "level", "", "Minimum level: debug|info|warning|error")
+	cmd.Flags().StringVar(&since, "since", "", "Only entries newer than a duration (e.g. 5m) or RFC 3339 time")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Only the newest N entries")
+	return cmd
+}
+
+// parseSince accepts a duration ("90s", "5m") relative to now or an RFC 3339 time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (want a duration like 5m or an RFC 3339 time)", s)
+}
+
+func printLogEntries(w io.Writer, entries []rpc.LogEntry) {
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s %-7s %-9s %s", e.Time.Local().Format("15:04:05.000"), strings.ToUpper(e.Level), e.Source, e.Text)
+		if e.URL != "" {
+			fmt.Fprintf(w, " (%s)", logLocation(e.URL, e.Line, e.Column))
+		}
+		fmt.Fprintln(w)
+		if e.Level == "error" {
+			for _, f := range e.Stack {
+				fn := f.Function
+				if fn == "" {
+					fn = "(anonymous)"
+				}
+				fmt.Fprintf(w, "    at %s (%s)\n", fn, logLocation(f.URL, f.Line, f.Column))
+			}
+		}
+	}
+}
+
+func logLocation(url string, line, col int64) string {
+	switch {
+	case line > 0 && col > 0:
+		return fmt.Sprintf("%s:%d:%d", url, line, col)
+	case line > 0:
+		return fmt.Sprintf("%s:%d", url, line)
+	default:
+		return url
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	if got, err := parseSince("5m", now); err != nil || !got.Equal(now.Add(-5*time.Minute)) {
+		t.Fatalf("duration: %v %v", got, err)
+	}
+	if got, err := parseSince("2025-01-02T15:00:00Z", now); err != nil || !got.Equal(time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("rfc3339: %v %v", got, err)
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPrintLogEntries(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local)
+	var buf bytes.Buffer
+	printLogEntries(&buf, []rpc.LogEntry{
+		{Time: ts, Level: "info", Source: "console", Text: "hello"},
+		{Time: ts, Level: "error", Source: "exception", Text: "Uncaught Error: boom", URL: "http://x/app.js", Line: 3, Column: 8,
+			Stack: []rpc.StackFrame{{Function: "run", URL: "http://x/app.js", Line: 3, Column: 8}, {URL: "http://x/app.js", Line: 10, Column: 1}}},
+	})
+	want := "15:04:05.000 INFO    console   hello\n" +
+		"15:04:05.000 ERROR   exception Uncaught Error: boom (http://x/app.js:3:8)\n" +
+		"    at run (http://x/app.js:3:8)\n" +
+		"    at (anonymous) (http://x/app.js:10:1)\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newNetworkCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Network tools (request log, response bodies, throttling)",
+	}
+	cmd.AddCommand(newNetworkLogCmd(root), newNetworkBodyCmd(root), newNetworkThrottleCmd(root))
+	return cmd
+}
+
+func newNetworkLogCmd(root *rootFlags) *cobra.Command {
+	var req rpc.NetworkLogRequest
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "List the requests of the current page load",
+		Long: `List the requests the tab made since its last navigation: URL, method, status,
+MIME type, size, timing, initiator and failure reason. Use the ID with
+"canvas network body" to fetch a response body.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			c = c.WithTab(root.tab)
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			out, err := c.NetworkLog(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			printNetworkRequests(os.Stdout, out.Requests)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&req.Filter, "filter", "", "Only URLs containing this string (case-insensitive)")
+	cmd.Flags().BoolVar(&req.Failed, "failed", false, "Only failed requests and HTTP errors")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func newNetworkBodyCmd(root *rootFlags) *cobra.Command {
+	var outPath string
+	cmd := &cobra.Command{
+		Use:   "body <request-id>",
+		Short: "Print a response body (Network.getResponseBody)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			out, err := c.NetworkBody(ctx, args[0])
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			if outPath != "" {
+				return os.WriteFile(outPath, out.Body, 0o644)
+			}
+			_, err = os.Stdout.Write(out.Body)
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the body to a file instead of stdout")
+	return cmd
+}
+
+func printNetworkRequests(w io.Writer, reqs []rpc.NetworkRequest) {
+	for _, r := range reqs {
+		status := "..."
+		switch {
+		case r.Failure != "":
+			status = "ERR"
+		case r.Status != 0:
+			status = fmt.Sprint(r.Status)
+		}
+		fmt.Fprintf(w, "%-12s %-3s %-6s %-10s %8s %7s  %s", r.ID, status, r.Method, r.Type, formatBytes(r.Size), formatMs(r.DurationMs), r.URL)
+		if r.Failure != "" {
+			fmt.Fprintf(w, " (%s)", r.Failure)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n <= 0:
+		return "-"
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1fKB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	}
+}
+
+func formatMs(ms float64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	if ms < 1000 {
+		return fmt.Sprintf("%.0fms", ms)
+	}
+	return fmt.Sprintf("%.2fs", ms/1000)
+}
+
+func newNetworkThrottleCmd(root *rootFlags) *cobra.Command {
+	var (
+		req  rpc.NetworkThrottleRequest
+		list bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "throttle",
+		Short: "Throttle the network or take it offline",
+		Long: `Throttle the network of the controlled tabs with a preset or custom values.
+Explicit values override the preset. Throttling survives reloads and browser
+restarts until turned off.`,
+		Example: `  canvas network throttle --preset slow-3g
+  canvas network throttle --latency 300 --download 1000 --upload 500
+  canvas network throttle --preset offline
+  canvas network throttle --off`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				return printNetworkPresets(root)
+			}
+			if req.Off && (req.Network != rpc.Network{}) {
+				return fmt.Errorf("--off cannot be combined with other throttle flags")
+			}
+			if !req.Off && (req.Network == rpc.Network{}) {
+				return fmt.Errorf("missing --preset, --latency/--download/--upload, --offline or --off")
+			}
+			return runEmulate(root, func(ctx context.Context, c *rpc.Client) (rpc.EmulationResponse, error) {
+				return c.NetworkThrottle(ctx, req)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Preset, "preset", "", "Preset: slow-3g|fast-3g|offline")
+	cmd.Flags().Float64Var(&req.LatencyMs, "latency", 0, "Added request latency in ms")
+	cmd.Flags().Float64Var(&req.DownloadKbps, "download", 0, "Download throughput in kbit/s")
+	cmd.Flags().Float64Var(&req.UploadKbps, "upload", 0, "Upload throughput in kbit/s")
+	cmd.Flags().BoolVar(&req.Offline, "offline", false, "Emulate a disconnected network")
+	cmd.Flags().BoolVar(&req.Off, "off", false, "Remove throttling")
+	cmd.Flags().BoolVar(&list, "list", false, "List presets")
+	return cmd
+}
+
+func printNetworkPresets(root *rootFlags) error {
+	presets := browser.NetworkPresets()
+	if root.jsonOutput {
+		return printJSON(presets)
+	}
+	for _, p := range presets {
+		fmt.Fprintln(os.Stdout, networkLine(rpc.Network{
+			Preset:       p.Preset,
+			Offline:      p.Offline,
+			LatencyMs:    p.LatencyMs,
+			DownloadKbps: p.DownloadKbps,
+			UploadKbps:   p.UploadKbps,
+		}))
+	}
+	return nil
+}
+
+func networkLine(n rpc.Network) string {
+	name := n.Preset
+	if name == "" {
+		name = "custom"
+	}
+	if n.Offline {
+		return name + ": offline"
+	}
+	return fmt.Sprintf("%s: latency %gms, down %s, up %s", name, n.LatencyMs, kbps(n.DownloadKbps), kbps(n.UploadKbps))
+}
+
+func kbps(v float64) string {
+	if v <= 0 {
+		return "unthrottled"
+	}
+	return fmt.Sprintf("%gkbit/s", v)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestNetworkThrottleCommand(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var got []rpc.NetworkThrottleRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/network/throttle", func(w http.ResponseWriter, r *http.Request) {
+			var req rpc.NetworkThrottleRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			got = append(got, req)
+			var out rpc.EmulationResponse
+			if !req.Off {
+				out.Emulation.Network = &rpc.Network{Preset: req.Preset, LatencyMs: 2000, DownloadKbps: 400, UploadKbps: 400}
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newNetworkCmd(&rootFlags{})
+		cmd.SetArgs(args)
+		var buf bytes.Buffer
+		restore, err := captureStdout(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Execute(); err != nil {
+			_ = restore()
+			t.Fatal(err)
+		}
+		_ = restore()
+		return buf.String()
+	}
+
+	if out := run("throttle", "--preset", "slow-3g"); out != "network: slow-3g: latency 2000ms, down 400kbit/s, up 400kbit/s\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if out := run("throttle", "--off"); out != "emulation: none\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if len(got) != 2 || got[0].Preset != "slow-3g" || got[0].Off || !got[1].Off {
+		t.Fatalf("unexpected requests: %#v", got)
+	}
+
+	cmd := newNetworkCmd(&rootFlags{})
+	cmd.SetArgs([]string{"throttle", "--off", "--preset", "offline"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error combining --off with --preset")
+	}
+}
+
+func TestNetworkLogCommand_Text(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var got rpc.NetworkLogRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/network/log", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(rpc.NetworkLogResponse{Requests: []rpc.NetworkRequest{
+				{ID: "1000.1", URL: "http://127.0.0.1:1/api", Method: "GET", Type: "Fetch", Status: 404, Size: 2048, DurationMs: 12.4, Finished: true},
+				{ID: "1000.2", URL: "https://fonts.invalid/f.woff2", Method: "GET", Type: "Font", Failure: "net::ERR_NAME_NOT_RESOLVED", DurationMs: 1500, Finished: true},
+			}})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newNetworkCmd(&rootFlags{})
+	cmd.SetArgs([]string{"log", "--failed", "--filter", "api"})
+
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	if !got.Failed || got.Filter != "api" {
+		t.Fatalf("unexpected request: %#v", got)
+	}
+	want := "1000.1       404 GET    Fetch         2.0KB    12ms  http://127.0.0.1:1/api\n" +
+		"1000.2       ERR GET    Font              -   1.50s  https://fonts.invalid/f.woff2 (net::ERR_NAME_NOT_RESOLVED)\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%q\nwant\n%q", buf.String(), want)
+	}
+}
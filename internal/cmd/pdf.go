@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newPDFCmd(root *rootFlags) *cobra.Command {
+	var (
+		outPath    string
+		paper      string
+		margin     string
+		landscape  bool
+		scale      float64
+		background bool
+		header     string
+		footer     string
+		pages      string
+		cssSize    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "pdf",
+		Short: "Print the current page to PDF",
+		Long: `Print the current page to PDF (Page.printToPDF), using the page's print styles.
+
+Lengths take in, cm, mm or px (a bare number is inches). --margin works like
+the CSS shorthand: "1cm", "1cm 2cm", or "top right bottom left".
+--header and --footer are HTML templates; elements with the classes date,
+title, url, pageNumber and totalPages get the print values, e.g.
+
+  canvas pdf --out report.pdf --footer '<div style="font-size:9px;margin:auto">
+    <span class="pageNumber"></span> / <span class="totalPages"></span></div>'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := rpc.PDFRequest{
+				Landscape:         landscape,
+				Background:        background,
+				HeaderTemplate:    header,
+				FooterTemplate:    footer,
+				PageRanges:        pages,
+				PreferCSSPageSize: cssSize,
+			}
+			var err error
+			if paper != "" {
+				if req.PaperWidth, req.PaperHeight, err = parsePaper(paper); err != nil {
+					return err
+				}
+			}
+			if margin != "" {
+				if req.Margins, err = parseMargins(margin); err != nil {
+					return err
+				}
+			}
+			if cmd.Flags().Changed("scale") {
+				if scale < 0.1 || scale > 2 {
+					return errors.New("--scale must be between 0.1 and 2")
+				}
+				req.Scale = scale
+			}
+
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			c = c.WithTab(root.tab).WithTimeout(2 * time.Minute)
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			out, err := c.PDF(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			b, err := base64.StdEncoding.DecodeString(out.Base64)
+			if err != nil {
+				return err
+			}
+
+			if outPath == "" {
+				outPath = fmt.Sprintf("canvas-%d.pdf", time.Now().UnixNano())
+			}
+			outPath = filepath.Clean(outPath)
+			if err := os.WriteFile(outPath, b, 0o644); err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(map[string]any{"path": outPath, "bytes": len(b)})
+			}
+			fmt.Fprintln(os.Stdout, outPath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Output file path (default: canvas-<ts>.pdf)")
+	cmd.Flags().StringVar(&paper, "paper", "", "Paper size: letter, legal, tabloid, ledger, a0-a6, or WxH like 210mmx297mm (default: letter)")
+	cmd.Flags().StringVar(&margin, "margin", "", "Page margins, CSS shorthand like 1cm or \"0.5in 1in\" (default: 1cm)")
+	cmd.Flags().BoolVar(&landscape, "landscape", false, "Landscape orientation")
+	cmd.Flags().Float64Var(&scale, "scale", 1, "Rendering scale, 0.1 to 2")
+	cmd.Flags().BoolVar(&background, "background", false, "Print background colors and images")
+	cmd.Flags().StringVar(&header, "header", "", "HTML template for the page header")
+	cmd.Flags().StringVar(&footer, "footer", "", "HTML template for the page footer")
+	cmd.Flags().StringVar(&pages, "pages", "", "Page ranges to print, e.g. 1-3,5 (default: all)")
+	cmd.Flags().BoolVar(&cssSize, "prefer-css-page-size", false, "Use the page size from CSS @page rules over --paper")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+// paperSizes are width x height in inches.
+var paperSizes = map[string][2]float64{
+	"letter":  {8.5, 11},
+	"legal":   {8.5, 14},
+	"tabloid": {11, 17},
+	"ledger":  {17, 11},
+	"a0":      {33.11, 46.81},
+	"a1":      {23.39, 33.11},
+	"a2":      {16.54, 23.39},
+	"a3":      {11.69, 16.54},
+	"a4":      {8.27, 11.69},
+	"a5":      {5.83, 8.27},
+	"a6":      {4.13, 5.83},
+}
+
+var paperSizeRE = regexp.MustCompile(`^([0-9.]+(?:in|cm|mm|px)?)x([0-9.]+(?:in|cm|mm|px)?)$`)
+
+// parsePaper returns the paper width and height in inches.
+func parsePaper(s string) (float64, float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if size, ok := paperSizes[s]; ok {
+		return size[0], size[1], nil
+	}
+	m := paperSizeRE.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("invalid --paper %q (want a name like a4 or WxH like 210mmx297mm)", s)
+	}
+	w, err := parseLength(m[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	h, err := parseLength(m[2])
+	if err != nil {
+		return 0, 0, err
+	}
+	if w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("invalid --paper %q", s)
+	}
+	return w, h, nil
+}
+
+// parseMargins parses 1 to 4 lengths like the CSS margin shorthand.
+func parseMargins(s string) (*rpc.PDFMargins, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 4 {
+		return nil, fmt.Errorf("invalid --margin %q (want 1 to 4 lengths)", s)
+	}
+	v := make([]float64, len(fields))
+	for i, f := range fields {
+		n, err := parseLength(f)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("invalid --margin %q", s)
+		}
+		v[i] = n
+	}
+	switch len(v) {
+	case 1:
+		return &rpc.PDFMargins{Top: v[0], Right: v[0], Bottom: v[0], Left: v[0]}, nil
+	case 2:
+		return &rpc.PDFMargins{Top: v[0], Right: v[1], Bottom: v[0], Left: v[1]}, nil
+	case 3:
+		return &rpc.PDFMargins{Top: v[0], Right: v[1], Bottom: v[2], Left: v[1]}, nil
+	default:
+		return &rpc.PDFMargins{Top: v[0], Right: v[1], Bottom: v[2], Left: v[3]}, nil
+	}
+}
+
+// parseLength converts a length with an optional unit to inches.
+func parseLength(s string) (float64, error) {
+	units := []struct {
+		suffix string
+		perIn  float64
+	}{{"in", 1}, {"cm", 2.54}, {"mm", 25.4}, {"px", 96}}
+	num, perIn := s, 1.0
+	for _, u := range units {
+		if n, ok := strings.CutSuffix(s, u.suffix); ok {
+			num, perIn = n, u.perIn
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid length %q (use in, cm, mm or px)", s)
+	}
+	return v / perIn, nil
+}
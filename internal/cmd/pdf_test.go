@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestParsePaper(t *testing.T) {
+	cases := map[string][2]float64{
+		"A4":          {8.27, 11.69},
+		"letter":      {8.5, 11},
+		"210mmx297mm": {210 / 25.4, 297 / 25.4},
+		"8.5x11":      {8.5, 11},
+		"800pxx600px": {800.0 / 96, 600.0 / 96},
+	}
+	for in, want := range cases {
+		w, h, err := parsePaper(in)
+		if err != nil || math.Abs(w-want[0]) > 1e-9 || math.Abs(h-want[1]) > 1e-9 {
+			t.Errorf("parsePaper(%q) = %v, %v, %v; want %v", in, w, h, err, want)
+		}
+	}
+	for _, in := range []string{"", "b5", "0x11", "10x", "10ptx11pt"} {
+		if _, _, err := parsePaper(in); err == nil {
+			t.Errorf("parsePaper(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseMargins(t *testing.T) {
+	cases := map[string]rpc.PDFMargins{
+		"0":             {},
+		"1in":           {Top: 1, Right: 1, Bottom: 1, Left: 1},
+		"1in 2.54cm":    {Top: 1, Right: 1, Bottom: 1, Left: 1},
+		"1 2":           {Top: 1, Right: 2, Bottom: 1, Left: 2},
+		"1 2 3":         {Top: 1, Right: 2, Bottom: 3, Left: 2},
+		"1 2 3 25.4mm":  {Top: 1, Right: 2, Bottom: 3, Left: 1},
+		" 96px  0.5in ": {Top: 1, Right: 0.5, Bottom: 1, Left: 0.5},
+	}
+	for in, want := range cases {
+		got, err := parseMargins(in)
+		if err != nil || *got != want {
+			t.Errorf("parseMargins(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "1 2 3 4 5", "-1cm", "1em"} {
+		if _, err := parseMargins(in); err == nil {
+			t.Errorf("parseMargins(%q): expected an error", in)
+		}
+	}
+}
+
+func TestPDFCommand_WritesFile(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	want := []byte("%PDF-1.4\n")
+	var got rpc.PDFRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/pdf", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(rpc.PDFResponse{Base64: base64.StdEncoding.EncodeToString(want)})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "report.pdf")
+	cmd := newPDFCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--out", outPath, "--paper", "letter", "--margin", "0", "--landscape", "--background",
+		"--scale", "0.5", "--footer", `<span class="pageNumber"></span>`, "--pages", "1-2"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantReq := rpc.PDFRequest{
+		PaperWidth: 8.5, PaperHeight: 11, Margins: &rpc.PDFMargins{}, Landscape: true, Scale: 0.5,
+		Background: true, FooterTemplate: `<span class="pageNumber"></span>`, PageRanges: "1-2",
+	}
+	if got.Margins == nil || *got.Margins != *wantReq.Margins {
+		t.Fatalf("margins = %+v", got.Margins)
+	}
+	got.Margins = wantReq.Margins
+	if got != wantReq {
+		t.Fatalf("request = %+v, want %+v", got, wantReq)
+	}
+	if b, err := os.ReadFile(outPath); err != nil || !bytes.Equal(b, want) {
+		t.Fatalf("file = %q, %v", b, err)
+	}
+	if buf.String() != outPath+"\n" {
+		t.Fatalf("output = %q", buf.String())
+	}
+}
+
+func TestPDFCommand_RejectsBadScale(t *testing.T) {
+	cmd := newPDFCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--scale", "3"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil || err.Error() != "--scale must be between 0.1 and 2" {
+		t.Fatalf("err = %v", err)
+	}
+}
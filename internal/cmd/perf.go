@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newPerfCmd(root *rootFlags) *cobra.Command {
+	var req rpc.PerfRequest
+	cmd := &cobra.Command{
+		Use:   "perf",
+		Short: "Report performance metrics and web vitals of the current page",
+		Long: `Report Chrome's performance counters (JS heap, nodes, layouts, script time),
+navigation timing (TTFB, FCP, DOMContentLoaded, load) and Core Web Vitals (LCP,
+CLS, INP approximated from buffered slow interactions, FID).
+
+With --reload, the page is reloaded before measuring; --runs N repeats that and
+reports the median and p95 of each value.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if req.Runs < 1 {
+				return fmt.Errorf("--runs must be at least 1")
+			}
+			if req.Runs > 1 {
+				req.Reload = true
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			timeout := 30*time.Second + time.Duration(req.Runs)*30*time.Second
+			c = c.WithTab(root.tab).WithTimeout(timeout)
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			out, err := c.Perf(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			printPerf(os.Stdout, out)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&req.Reload, "reload", false, "Reload the page before measuring")
+	cmd.Flags().IntVar(&req.Runs, "runs", 1, "Number of reloads to measure (implies --reload); reports median and p95")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func printPerf(w io.Writer, out rpc.PerfResponse) {
+	if out.URL != "" {
+		fmt.Fprintf(w, "url: %s\n", out.URL)
+	}
+	if out.Runs > 1 {
+		fmt.Fprintf(w, "runs: %d\n", out.Runs)
+		fmt.Fprintf(w, "%-24s %12s %12s\n", "metric", "median", "p95")
+		for _, v := range out.Values {
+			fmt.Fprintf(w, "%-24s %12s %12s\n", v.Name, formatPerfValue(v.Unit, v.Value), formatPerfValue(v.Unit, v.P95))
+		}
+		return
+	}
+	for _, v := range out.Values {
+		fmt.Fprintf(w, "%-24s %12s\n", v.Name, formatPerfValue(v.Unit, v.Value))
+	}
+}
+
+func formatPerfValue(unit string, v float64) string {
+	switch unit {
+	case "ms":
+		if v >= 1000 {
+			return fmt.Sprintf("%.2fs", v/1000)
+		}
+		return fmt.Sprintf("%.1fms", v)
+	case "bytes":
+		if v == 0 {
+			return "0B"
+		}
+		return formatBytes(int64(v))
+	case "score":
+		return fmt.Sprintf("%.3f", v)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestPrintPerf(t *testing.T) {
+	var buf bytes.Buffer
+	printPerf(&buf, rpc.PerfResponse{URL: "http://x/", Runs: 1, Values: []rpc.PerfValue{
+		{Name: "lcp", Unit: "ms", Value: 812.44},
+		{Name: "cls", Unit: "score", Value: 0.0123},
+		{Name: "js_heap_used", Unit: "bytes", Value: 3 * 1024 * 1024},
+		{Name: "nodes", Unit: "count", Value: 42},
+	}})
+	want := "url: http://x/\n" +
+		"lcp                           812.4ms\n" +
+		"cls                             0.012\n" +
+		"js_heap_used                    3.0MB\n" +
+		"nodes                              42\n"
+	if buf.String() != want {
+		t.Fatalf("single run:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	printPerf(&buf, rpc.PerfResponse{Runs: 5, Values: []rpc.PerfValue{{Name: "load", Unit: "ms", Value: 950, P95: 1200}}})
+	want = "runs: 5\n" +
+		"metric                         median          p95\n" +
+		"load                          950.0ms        1.20s\n"
+	if buf.String() != want {
+		t.Fatalf("runs:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newProfileCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "JavaScript CPU profiles and heap snapshots",
+	}
+	cmd.AddCommand(newProfileCPUCmd(root), newProfileHeapCmd(root))
+	return cmd
+}
+
+func newProfileCPUCmd(root *rootFlags) *cobra.Command {
+	var (
+		duration time.Duration
+		interval time.Duration
+		outPath  string
+		top      int
+	)
+	cmd := &cobra.Command{
+		Use:   "cpu",
+		Short: "Record a JavaScript CPU profile and summarize the hottest functions",
+		Long: `Sample the tab's JavaScript for --duration (Profiler domain), print the functions
+with the most self time and, with --out, write a .cpuprofile that loads in the
+DevTools Performance panel. The page stays usable while sampling, so you can
+drive it from another terminal.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if duration <= 0 {
+				return errors.New("--duration must be positive")
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			timeout := duration + 30*time.Second
+			c = c.WithTab(root.tab).WithTimeout(timeout)
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			out, err := c.ProfileCPU(ctx, rpc.ProfileCPURequest{
+				DurationMs: duration.Milliseconds(),
+				IntervalUs: interval.Microseconds(),
+				Top:        top,
+			})
+			cancel()
+			if err != nil {
+				return err
+			}
+			if outPath != "" {
+				if err := os.WriteFile(outPath, out.Profile, 0o644); err != nil {
+					return err
+				}
+			}
+			if root.jsonOutput {
+				return printJSON(out.Summary)
+			}
+			printProfileSummary(os.Stdout, out.Summary)
+			if outPath != "" {
+				fmt.Fprintf(os.Stdout, "wrote %s\n", outPath)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&duration, "duration", 5*time.Second, "How long to sample")
+	cmd.Flags().DurationVar(&interval, "interval", 0, "Sampling interval, e.g. 100us (default: Chrome's)")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the profile to this .cpuprofile file")
+	cmd.Flags().IntVar(&top, "top", 15, "Number of functions in the summary")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func newProfileHeapCmd(root *rootFlags) *cobra.Command {
+	var outPath string
+	cmd := &cobra.Command{
+		Use:   "heap",
+		Short: "Take a heap snapshot (.heapsnapshot for the DevTools Memory panel)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outPath == "" {
+				return errors.New("missing --out (e.g. --out page.heapsnapshot)")
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			c = c.WithTab(root.tab).WithTimeout(5 * time.Minute)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			n, err := writeFileFrom(outPath, func(w io.Writer) error { return c.ProfileHeap(ctx, w) })
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(struct {
+					Path  string `json:"path"`
+					Bytes int64  `json:"bytes"`
+				}{outPath, n})
+			}
+			fmt.Fprintf(os.Stdout, "wrote %s (%s)\n", outPath, formatBytes(n))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the snapshot to this file")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func printProfileSummary(w io.Writer, s rpc.CPUProfileSummary) {
+	idle := 0.0
+	if s.DurationMs > 0 {
+		idle = s.IdleMs / s.DurationMs * 100
+	}
+	fmt.Fprintf(w, "profile: %s, %d samples, %.1f%% idle\n", formatPerfValue("ms", s.DurationMs), s.Samples, idle)
+	if len(s.Top) == 0 {
+		fmt.Fprintln(w, "no JavaScript ran")
+		return
+	}
+	busy := s.DurationMs - s.IdleMs
+	fmt.Fprintf(w, "%10s %6s  %s\n", "self", "busy%", "function")
+	for _, f := range s.Top {
+		share := 0.0
+		if busy > 0 {
+			share = f.SelfMs / busy * 100
+		}
+		fmt.Fprintf(w, "%10s %5.1f%%  %s", formatPerfValue("ms", f.SelfMs), share, f.Function)
+		if f.URL != "" {
+			fmt.Fprintf(w, " (%s:%d:%d)", f.URL, f.Line, f.Column)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestPrintProfileSummary(t *testing.T) {
+	var buf bytes.Buffer
+	printProfileSummary(&buf, rpc.CPUProfileSummary{
+		DurationMs: 5000,
+		Samples:    4000,
+		IdleMs:     4000,
+		Top: []rpc.ProfileFunction{
+			{Function: "render", URL: "http://x/app.js", Line: 10, Column: 5, SelfMs: 600, Samples: 480},
+			{Function: "(garbage collector)", SelfMs: 100, Samples: 80},
+		},
+	})
+	want := "profile: 5.00s, 4000 samples, 80.0% idle\n" +
+		"      self  busy%  function\n" +
+		"   600.0ms  60.0%  render (http://x/app.js:10:5)\n" +
+		"   100.0ms  10.0%  (garbage collector)\n"
+	if buf.String() != want {
+		t.Fatalf("summary:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestProfileHeapWritesFile(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/profile/heap", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"snapshot":{}}`))
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "page.heapsnapshot")
+	cmd := newProfileCmd(&rootFlags{})
+	cmd.SetArgs([]string{"heap", "--out", out})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != `{"snapshot":{}}` {
+		t.Fatalf("snapshot = %q, %v", data, err)
+	}
+	if buf.String() != "wrote "+out+" (15B)\n" {
+		t.Fatalf("output = %q", buf.String())
+	}
+}
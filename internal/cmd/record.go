@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/screencast"
+)
+
+func newRecordCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Record a screencast of the tab",
+		Long: `Record what happens in the tab (Page.startScreencast) and save it as an
+animated GIF, or as a directory of JPEG frames with index.json and an ffmpeg
+concat script:
+
+  canvas record start
+  # … drive the page …
+  canvas record stop --out session.gif
+  canvas record stop --out frames/   # then, for a video:
+  ffmpeg -f concat -i frames/frames.ffconcat -pix_fmt yuv420p session.webm
+
+Chrome only sends frames when the page changes.`,
+	}
+	cmd.AddCommand(newRecordStartCmd(root), newRecordStopCmd(root))
+	return cmd
+}
+
+func newRecordStartCmd(root *rootFlags) *cobra.Command {
+	var req rpc.RecordStartRequest
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start recording the tab",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			out, err := c.WithTab(root.tab).RecordStart(ctx, req)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			fmt.Fprintf(os.Stdout, "recording tab %s (stop with `canvas record stop --out session.gif`)\n", out.Tab)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&req.Quality, "quality", 80, "JPEG quality of the frames, 0-100")
+	cmd.Flags().IntVar(&req.MaxWidth, "max-width", 0, "Scale frames down to at most this width (default: viewport)")
+	cmd.Flags().IntVar(&req.MaxHeight, "max-height", 0, "Scale frames down to at most this height (default: viewport)")
+	cmd.Flags().IntVar(&req.EveryNthFrame, "every-nth", 0, "Keep only every n-th frame Chrome paints")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func newRecordStopCmd(root *rootFlags) *cobra.Command {
+	var (
+		outPath string
+		fps     float64
+	)
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop recording and save a GIF or a frames directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outPath == "" {
+				return errors.New("missing --out (session.gif, or a directory for the frames)")
+			}
+			ext := strings.ToLower(filepath.Ext(outPath))
+			switch ext {
+			case ".webm", ".mp4", ".mov", ".mkv":
+				return fmt.Errorf("canvas can't encode %s itself; record to a frames directory and convert it with ffmpeg (see canvas record --help)", ext)
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			c = c.WithTimeout(10 * time.Minute)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+
+			if ext == ".gif" {
+				n, err := writeFileFrom(outPath, func(w io.Writer) error {
+					return c.RecordStop(ctx, rpc.RecordStopRequest{Format: "gif", FPS: fps}, w)
+				})
+				if err != nil {
+					return err
+				}
+				if root.jsonOutput {
+					return printJSON(map[string]any{"path": outPath, "bytes": n})
+				}
+				fmt.Fprintf(os.Stdout, "wrote %s (%s)\n", outPath, formatBytes(n))
+				return nil
+			}
+
+			if err := os.MkdirAll(outPath, 0o755); err != nil {
+				return err
+			}
+			pr, pw := io.Pipe()
+			go func() {
+				pw.CloseWithError(c.RecordStop(ctx, rpc.RecordStopRequest{Format: "frames"}, pw))
+			}()
+			frames, err := extractFrames(pr, outPath)
+			_ = pr.CloseWithError(err)
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(map[string]any{"path": outPath, "frames": frames})
+			}
+			fmt.Fprintf(os.Stdout, "wrote %d frames to %s\n", frames, outPath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "session.gif, or a directory for JPEG frames")
+	cmd.Flags().Float64Var(&fps, "fps", 0, "Thin GIFs to about this many frames per second (default: 10)")
+	return cmd
+}
+
+// extractFrames unpacks the frames tar into dir and returns the number of
+// frames.
+func extractFrames(r io.Reader, dir string) (int, error) {
+	tr := tar.NewReader(r)
+	frames := 0
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return frames, err
+		}
+		name := filepath.Base(h.Name)
+		if name != h.Name || h.Typeflag != tar.TypeReg {
+			return frames, fmt.Errorf("unexpected entry %q in recording", h.Name)
+		}
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return frames, err
+		}
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return frames, err
+		}
+		if name != screencast.IndexFile && name != screencast.ConcatFile {
+			frames++
+		}
+	}
+	if frames == 0 {
+		return 0, errors.New("no frames received")
+	}
+	return frames, nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/screencast"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func startRecordServer(t *testing.T, got *rpc.RecordStopRequest) {
+	t.Helper()
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	t0 := time.Now()
+	rec := screencast.Recording{
+		Frames: []screencast.Frame{{JPEG: []byte("one"), Time: t0}, {JPEG: []byte("two"), Time: t0.Add(time.Second)}},
+		End:    t0.Add(2 * time.Second),
+	}
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/record/stop", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(got)
+			if got.Format == "gif" {
+				_, _ = w.Write([]byte("GIF89a"))
+				return
+			}
+			_ = screencast.WriteFramesTar(w, rec)
+		})
+	})
+	t.Cleanup(shutdown)
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runRecordStop(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newRecordCmd(&rootFlags{})
+	cmd.SetArgs(append([]string{"stop"}, args...))
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	return buf.String(), err
+}
+
+func TestRecordStopWritesGIF(t *testing.T) {
+	var got rpc.RecordStopRequest
+	startRecordServer(t, &got)
+
+	out := filepath.Join(t.TempDir(), "session.gif")
+	stdout, err := runRecordStop(t, "--out", out, "--fps", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (rpc.RecordStopRequest{Format: "gif", FPS: 5}) {
+		t.Fatalf("request = %+v", got)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "GIF89a" {
+		t.Fatalf("gif = %q, %v", data, err)
+	}
+	if stdout != "wrote "+out+" (6B)\n" {
+		t.Fatalf("output = %q", stdout)
+	}
+}
+
+func TestRecordStopExtractsFrames(t *testing.T) {
+	var got rpc.RecordStopRequest
+	startRecordServer(t, &got)
+
+	dir := filepath.Join(t.TempDir(), "frames")
+	stdout, err := runRecordStop(t, "--out", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Format != "frames" {
+		t.Fatalf("request = %+v", got)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "frame-00002.jpg")); err != nil || string(data) != "two" {
+		t.Fatalf("frame = %q, %v", data, err)
+	}
+	for _, name := range []string{screencast.IndexFile, screencast.ConcatFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stdout != "wrote 2 frames to "+dir+"\n" {
+		t.Fatalf("output = %q", stdout)
+	}
+}
+
+func TestRecordStopRejectsVideo(t *testing.T) {
+	_, err := runRecordStop(t, "--out", "session.webm")
+	if err == nil || !strings.Contains(err.Error(), "ffmpeg") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -10,14 +10,16 @@ import (
 )
 
 func newReloadCmd(root *rootFlags) *cobra.Command {
+	var failOnError bool
 	cmd := &cobra.Command{
 		Use:   "reload",
 		Short: "Reload the controlled tab",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab)
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			out, err := c.Reload(ctx)
 			cancel()
@@ -25,11 +27,17 @@ func newReloadCmd(root *rootFlags) *cobra.Command {
 				return err
 			}
 			if root.jsonOutput {
-				return printJSON(out)
+				if err := printJSON(out); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(os.Stdout, "ok")
+				printLoadReport(os.Stderr, out.LoadReport)
 			}
-			fmt.Fprintln(os.Stdout, "ok")
-			return nil
+			return checkLoadReport(out.LoadReport, failOnError)
 		},
 	}
+	addTabFlag(cmd, root)
+	addFailOnErrorFlag(cmd, &failOnError)
 	return cmd
 }
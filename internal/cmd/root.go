@@ -1,13 +1,31 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/state"
 )
 
 var version = "dev"
 
 type rootFlags struct {
 	jsonOutput bool
+	session    string
+	tab        string
+}
+
+// sessionName resolves --session, falling back to $CANVAS_SESSION.
+func (f *rootFlags) sessionName() string {
+	if f.session != "" {
+		return f.session
+	}
+	return os.Getenv("CANVAS_SESSION")
+}
+
+func (f *rootFlags) stateDir() (string, error) {
+	return state.SessionStateDir(f.sessionName())
 }
 
 func newRootCmd() *cobra.Command {
@@ -21,6 +39,7 @@ func newRootCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().BoolVar(&flags.jsonOutput, "json", false, "Output JSON when supported")
+	cmd.PersistentFlags().StringVar(&flags.session, "session", "", "Session name (default: $CANVAS_SESSION or \"default\")")
 	cmd.Version = version
 	cmd.SetVersionTemplate("{{.Version}}\n")
 
@@ -30,6 +49,7 @@ func newRootCmd() *cobra.Command {
 		newDaemonCmd(),
 		newStatusCmd(&flags),
 		newStopCmd(&flags),
+		newSessionsCmd(&flags),
 		newFocusCmd(&flags),
 		newDevToolsCmd(&flags),
 		newGotoCmd(&flags),
@@ -37,6 +57,20 @@ func newRootCmd() *cobra.Command {
 		newReloadCmd(&flags),
 		newDomCmd(&flags),
 		newScreenshotCmd(&flags),
+		newPDFCmd(&flags),
+		newRecordCmd(&flags),
+		newSnapshotCmd(&flags),
+		newA11yCmd(&flags),
+		newTabCmd(&flags),
+		newEmulateCmd(&flags),
+		newNetworkCmd(&flags),
+		newLogsCmd(&flags),
+		newHARCmd(&flags),
+		newRouteCmd(&flags),
+		newPerfCmd(&flags),
+		newTraceCmd(&flags),
+		newProfileCmd(&flags),
+		newCoverageCmd(&flags),
 	)
 
 	return cmd
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/daemon"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newRouteCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "route",
+		Short: "Mock responses for matching requests",
+		Long: `Answer requests whose URL matches a pattern with a canned response (or a network
+error) instead of hitting the network. * matches any run of characters; the
+query string is ignored unless the pattern contains '?'. The first matching
+route wins. Load routes at startup with "canvas start --routes routes.json".`,
+	}
+	cmd.AddCommand(newRouteAddCmd(root), newRouteListCmd(root), newRouteRemoveCmd(root), newRouteClearCmd(root))
+	return cmd
+}
+
+func newRouteAddCmd(root *rootFlags) *cobra.Command {
+	var (
+		r           rpc.Route
+		body        string
+		bodyFile    string
+		headers     []string
+		contentType string
+		delay       time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "add <pattern>",
+		Short: "Add a mock route",
+		Example: `  canvas route add "*/api/users" --body-file users.json
+  canvas route add "*/api/save" --method POST --status 500 --body '{"error":"nope"}'
+  canvas route add "*://analytics.example/*" --fail BlockedByClient`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r.Pattern = args[0]
+			if body != "" && bodyFile != "" {
+				return errors.New("use either --body or --body-file")
+			}
+			for _, h := range headers {
+				k, v, ok := strings.Cut(h, ":")
+				if !ok || strings.TrimSpace(k) == "" {
+					return fmt.Errorf("invalid --header %q (want \"Name: value\")", h)
+				}
+				if r.Headers == nil {
+					r.Headers = map[string]string{}
+				}
+				r.Headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			}
+			switch {
+			case bodyFile != "":
+				data, err := os.ReadFile(bodyFile)
+				if err != nil {
+					return err
+				}
+				r.Body = data
+				if contentType == "" {
+					contentType = mime.TypeByExtension(filepath.Ext(bodyFile))
+				}
+			case body != "":
+				r.Body = []byte(body)
+			}
+			if contentType != "" {
+				if r.Headers == nil {
+					r.Headers = map[string]string{}
+				}
+				r.Headers["Content-Type"] = contentType
+			}
+			r.DelayMs = delay.Milliseconds()
+
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			out, err := c.RouteAdd(ctx, r)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			printRoutes(os.Stdout, out.Routes)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&r.Method, "method", "", "Only match this HTTP method")
+	cmd.Flags().Int64Var(&r.Status, "status", 200, "Response status")
+	cmd.Flags().StringVar(&body, "body", "", "Response body")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "Read the response body from a file (Content-Type from its extension)")
+	cmd.Flags().StringArrayVar(&headers, "header", nil, `Response header "Name: value" (repeatable)`)
+	cmd.Flags().StringVar(&contentType, "content-type", "", "Response Content-Type")
+	cmd.Flags().StringVar(&r.Fail, "fail", "", "Fail with a network error instead (e.g. Failed, TimedOut, ConnectionRefused, BlockedByClient)")
+	cmd.Flags().DurationVar(&delay, "delay", 0, "Hold the response back, e.g. 2s")
+	return cmd
+}
+
+func newRouteListCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List mock routes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoutes(root, func(ctx context.Context, c *rpc.Client) (rpc.RoutesResponse, error) {
+				return c.RouteList(ctx)
+			})
+		},
+	}
+}
+
+func newRouteRemoveCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <id>...",
+		Short: "Remove mock routes",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoutes(root, func(ctx context.Context, c *rpc.Client) (rpc.RoutesResponse, error) {
+				return c.RouteRemove(ctx, args)
+			})
+		},
+	}
+}
+
+func newRouteClearCmd(root *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all mock routes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoutes(root, func(ctx context.Context, c *rpc.Client) (rpc.RoutesResponse, error) {
+				return c.RouteClear(ctx)
+			})
+		},
+	}
+}
+
+func runRoutes(root *rootFlags, call func(context.Context, *rpc.Client) (rpc.RoutesResponse, error)) error {
+	c, _, _, err := mustClient(root)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	out, err := call(ctx, c)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if root.jsonOutput {
+		return printJSON(out)
+	}
+	printRoutes(os.Stdout, out.Routes)
+	return nil
+}
+
+func printRoutes(w io.Writer, routes []rpc.Route) {
+	if len(routes) == 0 {
+		fmt.Fprintln(w, "routes: none")
+		return
+	}
+	for _, r := range routes {
+		method := r.Method
+		if method == "" {
+			method = "*"
+		}
+		answer := fmt.Sprintf("%d %s", r.Status, formatBytes(int64(len(r.Body))))
+		if r.Fail != "" {
+			answer = "fail " + r.Fail
+		}
+		if r.DelayMs > 0 {
+			answer += fmt.Sprintf(" after %dms", r.DelayMs)
+		}
+		fmt.Fprintf(w, "%-4s %-6s %s -> %s (hits: %d)\n", r.ID, method, r.Pattern, answer, r.Hits)
+	}
+}
+
+// checkRoutesFile validates a routes file up front (the daemon would only
+// fail in its log) and returns its absolute path.
+func checkRoutesFile(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := daemon.LoadRoutes(abs); err != nil {
+		return "", err
+	}
+	return abs, nil
+}
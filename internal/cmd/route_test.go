@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestRouteAddCommand(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var got rpc.RouteAddRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/route/add", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			rr := got.Route
+			rr.ID = "r1"
+			_ = json.NewEncoder(w).Encode(rpc.RoutesResponse{Routes: []rpc.Route{rr}})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	bodyFile := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(bodyFile, []byte(`[{"id":1}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newRouteCmd(&rootFlags{})
+	cmd.SetArgs([]string{"add", "*/api/users", "--method", "GET", "--body-file", bodyFile, "--header", "X-Mock: yes", "--delay", "1.5s"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := got.Route
+	if r.Pattern != "*/api/users" || r.Method != "GET" || r.Status != 200 || string(r.Body) != `[{"id":1}]` || r.DelayMs != 1500 {
+		t.Fatalf("unexpected route: %+v", r)
+	}
+	if r.Headers["X-Mock"] != "yes" || r.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("unexpected headers: %+v", r.Headers)
+	}
+	if want := "r1   GET    */api/users -> 200 10B after 1500ms (hits: 0)\n"; buf.String() != want {
+		t.Fatalf("output = %q, want %q", buf.String(), want)
+	}
+}
@@ -1,59 +1,237 @@
 package cmd
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"image/png"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/contactsheet"
+	"github.com/steipete/canvas/internal/rpc"
 )
 
 func newScreenshotCmd(root *rootFlags) *cobra.Command {
 	var (
 		selector string
 		outPath  string
+		clip     string
+		widths   string
+		req      rpc.ScreenshotRequest
 	)
 
 	cmd := &cobra.Command{
 		Use:   "screenshot",
 		Short: "Take a screenshot of the controlled tab",
+		Long: `Take a screenshot of the viewport, the full page (--full-page), an element
+(--selector) or a region (--clip x,y,w,h in CSS pixels of the document).
+
+The format follows --format, or the --out extension (.png, .jpg, .webp).
+
+--widths 375,768,1280 captures the full page at each viewport width instead,
+writes <out>-375.png, … and a labelled contact sheet to --out, then restores
+the viewport.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, _, err := mustClient()
-			if err != nil {
-				return err
+			if widths != "" {
+				return screenshotWidths(root, widths, outPath, selector, clip, req)
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			out, err := c.Screenshot(ctx, selector)
-			cancel()
-			if err != nil {
-				return err
+			req.Selector = selector
+			if req.Format == "" {
+				req.Format = formatFromExt(outPath)
+			}
+			req.Format = strings.ToLower(req.Format)
+			if req.Format == "jpg" {
+				req.Format = "jpeg"
+			}
+			if clip != "" {
+				r, err := parseClip(clip)
+				if err != nil {
+					return err
+				}
+				req.Clip = r
 			}
 
-			b, err := base64.StdEncoding.DecodeString(out.Base64)
+			c, _, _, err := mustClient(root)
 			if err != nil {
 				return err
 			}
+			c = c.WithTab(root.tab).WithTimeout(2 * time.Minute)
 
 			if outPath == "" {
-				outPath = fmt.Sprintf("canvas-%d.png", time.Now().UnixNano())
+				ext := cmp.Or(req.Format, "png")
+				if ext == "jpeg" {
+					ext = "jpg"
+				}
+				outPath = fmt.Sprintf("canvas-%d.%s", time.Now().UnixNano(), ext)
 			}
 			outPath = filepath.Clean(outPath)
-			if err := os.WriteFile(outPath, b, 0o644); err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			n, err := writeFileFrom(outPath, func(w io.Writer) error { return screenshotTo(ctx, c, req, w) })
+			cancel()
+			if err != nil {
 				return err
 			}
 
 			if root.jsonOutput {
-				return printJSON(map[string]any{"path": outPath, "bytes": len(b)})
+				return printJSON(map[string]any{"path": outPath, "bytes": n})
 			}
 			fmt.Fprintln(os.Stdout, outPath)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&selector, "selector", "", "CSS selector to screenshot (default: full page)")
-	cmd.Flags().StringVar(&outPath, "out", "", "Output file path (default: canvas-<ts>.png)")
+	cmd.Flags().StringVar(&selector, "selector", "", "CSS selector to screenshot (default: the viewport)")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Output file path (default: canvas-<ts>.png)")
+	cmd.Flags().StringVar(&req.Format, "format", "", "Image format: png, jpeg or webp (default: from --out, else png)")
+	cmd.Flags().IntVar(&req.Quality, "quality", 0, "Compression quality 0-100 (jpeg and webp)")
+	cmd.Flags().BoolVar(&req.FullPage, "full-page", false, "Capture the whole page, beyond the viewport")
+	cmd.Flags().StringVar(&clip, "clip", "", "Capture a region: x,y,width,height in CSS pixels")
+	cmd.Flags().Float64Var(&req.Scale, "scale", 0, "Resize the capture, e.g. 0.5 or 2 (default: 1)")
+	cmd.Flags().BoolVar(&req.OmitBackground, "omit-background", false, "Transparent background where the page paints none (png, webp)")
+	cmd.Flags().Float64Var(&req.Padding, "padding", 0, "CSS pixels to include around --selector")
+	cmd.Flags().StringVar(&widths, "widths", "", "Full-page shots at these viewport widths plus a contact sheet, e.g. 375,768,1280,1920")
+	addTabFlag(cmd, root)
 	return cmd
 }
+
+// screenshotTo streams the screenshot into w. Daemons from before
+// /screenshot/raw only answer with base64 JSON.
+func screenshotTo(ctx context.Context, c *rpc.Client, req rpc.ScreenshotRequest, w io.Writer) error {
+	err := c.ScreenshotTo(ctx, req, w)
+	var se *rpc.StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		return err
+	}
+	out, err := c.Screenshot(ctx, req)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, base64.NewDecoder(base64.StdEncoding, strings.NewReader(out.Base64)))
+	return err
+}
+
+// screenshotWidths implements --widths: one full-page PNG per width next to
+// outPath, and the contact sheet of all of them at outPath.
+func screenshotWidths(root *rootFlags, widths, outPath, selector, clip string, req rpc.ScreenshotRequest) error {
+	list, err := parseWidths(widths)
+	if err != nil {
+		return err
+	}
+	switch {
+	case selector != "" || clip != "":
+		return errors.New("--widths captures the full page; drop --selector and --clip")
+	case req.Format != "" && req.Format != "png", formatFromExt(outPath) != "", req.Quality != 0:
+		return errors.New("--widths writes PNGs")
+	case req.Padding != 0:
+		return errors.New("--padding only applies to --selector")
+	}
+	if outPath == "" {
+		outPath = fmt.Sprintf("canvas-%d.png", time.Now().UnixNano())
+	}
+	outPath = filepath.Clean(outPath)
+	stem := strings.TrimSuffix(outPath, filepath.Ext(outPath))
+
+	c, _, _, err := mustClient(root)
+	if err != nil {
+		return err
+	}
+	c = c.WithTab(root.tab).WithTimeout(5 * time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	res, err := c.ScreenshotWidths(ctx, rpc.ScreenshotWidthsRequest{Widths: list, Scale: req.Scale, OmitBackground: req.OmitBackground})
+	if err != nil {
+		return err
+	}
+
+	type shotFile struct {
+		Width  int    `json:"width"`
+		Path   string `json:"path"`
+		Height int    `json:"height"`
+	}
+	var (
+		files []shotFile
+		items []contactsheet.Item
+	)
+	for _, s := range res.Shots {
+		data, err := base64.StdEncoding.DecodeString(s.Base64)
+		if err != nil {
+			return fmt.Errorf("width %d: %w", s.Width, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("width %d: %w", s.Width, err)
+		}
+		path := fmt.Sprintf("%s-%d.png", stem, s.Width)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+		files = append(files, shotFile{Width: s.Width, Path: path, Height: img.Bounds().Dy()})
+		items = append(items, contactsheet.Item{Label: fmt.Sprintf("%dpx", s.Width), Image: img})
+	}
+	if _, err := writeFileFrom(outPath, func(w io.Writer) error { return png.Encode(w, contactsheet.Compose(items)) }); err != nil {
+		return err
+	}
+
+	if root.jsonOutput {
+		return printJSON(map[string]any{"sheet": outPath, "shots": files})
+	}
+	for _, f := range files {
+		fmt.Fprintln(os.Stdout, f.Path)
+	}
+	fmt.Fprintln(os.Stdout, outPath)
+	return nil
+}
+
+func parseWidths(s string) ([]int, error) {
+	var out []int
+	for p := range strings.SplitSeq(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(p), "px"))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid --widths %q (want e.g. 375,768,1280)", s)
+		}
+		if slices.Contains(out, n) {
+			return nil, fmt.Errorf("width %d given twice", n)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func formatFromExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		return "jpeg"
+	case ".webp":
+		return "webp"
+	default:
+		return ""
+	}
+}
+
+func parseClip(s string) (*rpc.ClipRect, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid --clip %q (want x,y,width,height)", s)
+	}
+	var v [4]float64
+	for i, p := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --clip %q (want x,y,width,height)", s)
+		}
+		v[i] = n
+	}
+	return &rpc.ClipRect{X: v[0], Y: v[1], Width: v[2], Height: v[3]}, nil
+}
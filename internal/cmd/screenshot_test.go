@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestScreenshotCommand_StreamsRawImage(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	want := []byte("RIFF....WEBPVP8 ")
+	var got rpc.ScreenshotRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/screenshot/raw", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.Header().Set("Content-Type", "image/webp")
+			_, _ = w.Write(want)
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "shot.webp")
+	cmd := newScreenshotCmd(&rootFlags{})
+	cmd.SetArgs([]string{"-o", outPath, "--quality", "70", "--clip", "0, 100, 640,480", "--scale", "0.5", "--omit-background"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantReq := rpc.ScreenshotRequest{Format: "webp", Quality: 70, Scale: 0.5, OmitBackground: true}
+	if got.Clip == nil || *got.Clip != (rpc.ClipRect{X: 0, Y: 100, Width: 640, Height: 480}) {
+		t.Fatalf("clip = %+v", got.Clip)
+	}
+	got.Clip = nil
+	if got != wantReq {
+		t.Fatalf("request = %+v, want %+v", got, wantReq)
+	}
+	if b, err := os.ReadFile(outPath); err != nil || !bytes.Equal(b, want) {
+		t.Fatalf("file = %q, %v", b, err)
+	}
+}
+
+func TestScreenshotCommand_Widths(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	white := color.NRGBA{255, 255, 255, 255}
+	var got rpc.ScreenshotWidthsRequest
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/screenshot/widths", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			var out rpc.ScreenshotWidthsResponse
+			for _, width := range got.Widths {
+				data := solidPNG(t, width/10, 50, white)
+				out.Shots = append(out.Shots, rpc.WidthScreenshot{Width: width, Base64: base64.StdEncoding.EncodeToString(data)})
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		})
+	})
+	t.Cleanup(shutdown)
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "home.png")
+	cmd := newScreenshotCmd(&rootFlags{})
+	cmd.SetArgs([]string{"-o", outPath, "--widths", "375, 1280px"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Widths) != 2 || got.Widths[0] != 375 || got.Widths[1] != 1280 {
+		t.Fatalf("widths = %v", got.Widths)
+	}
+	for _, name := range []string{"home-375.png", "home-1280.png"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.Open(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sheet, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := sheet.Bounds(); b.Dx() <= 37+128 || b.Dy() <= 50 {
+		t.Fatalf("contact sheet is %dx%d", b.Dx(), b.Dy())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("home-1280.png")) {
+		t.Fatalf("output = %q", buf.String())
+	}
+}
+
+func TestParseWidths(t *testing.T) {
+	if got, err := parseWidths("375,768, 1280px"); err != nil || len(got) != 3 || got[2] != 1280 {
+		t.Fatalf("parseWidths = %v, %v", got, err)
+	}
+	for _, in := range []string{"", "375,", "0", "abc", "375,375"} {
+		if _, err := parseWidths(in); err == nil {
+			t.Errorf("parseWidths(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseClip(t *testing.T) {
+	if r, err := parseClip("10,20.5,300,200"); err != nil || *r != (rpc.ClipRect{X: 10, Y: 20.5, Width: 300, Height: 200}) {
+		t.Fatalf("parseClip = %+v, %v", r, err)
+	}
+	for _, in := range []string{"", "1,2,3", "a,b,c,d", "1,2,3,4,5"} {
+		if _, err := parseClip(in); err == nil {
+			t.Errorf("parseClip(%q): expected an error", in)
+		}
+	}
+}
+
+func TestFormatFromExt(t *testing.T) {
+	for in, want := range map[string]string{"a.PNG": "", "a.jpg": "jpeg", "b.jpeg": "jpeg", "c.webp": "webp", "": ""} {
+		if got := formatFromExt(in); got != want {
+			t.Errorf("formatFromExt(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -19,18 +19,29 @@ func newServeCmd(root *rootFlags) *cobra.Command {
 		app          bool
 		windowSize   string
 		browserBin   string
+		attach       string
+		adoptTab     bool
 		stealth      bool
+		routesFile   string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Run canvas in the foreground",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			stateDir, err := state.DefaultStateDir()
+			stateDir, err := root.stateDir()
 			if err != nil {
 				return err
 			}
 
+			if routesFile != "" {
+				abs, err := checkRoutesFile(routesFile)
+				if err != nil {
+					return err
+				}
+				routesFile = abs
+			}
+
 			tempDir := false
 			if dir == "" {
 				d, err := os.MkdirTemp("", "canvas-*")
@@ -50,6 +61,7 @@ func newServeCmd(root *rootFlags) *cobra.Command {
 			}
 
 			cfg := daemon.Config{
+				Session:      sessionOrDefault(root.sessionName()),
 				StateDir:     stateDir,
 				ServeDir:     dir,
 				HTTPPort:     port,
@@ -58,9 +70,12 @@ func newServeCmd(root *rootFlags) *cobra.Command {
 				App:          app,
 				WindowSize:   windowSize,
 				BrowserBin:   browserBin,
+				Attach:       attach,
+				AdoptTab:     adoptTab,
 				Stealth:      stealth,
 				TempDir:      tempDir,
 				Watch:        true,
+				RoutesFile:   routesFile,
 			}
 
 			if err := daemon.Run(cfg); err != nil && !errors.Is(err, os.ErrClosed) {
@@ -78,5 +93,8 @@ func newServeCmd(root *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&stealth, "stealth", true, "Best-effort automation detection reduction")
 	cmd.Flags().StringVar(&windowSize, "window-size", "1280,720", "Browser window size, e.g. 1280,720")
 	cmd.Flags().StringVar(&browserBin, "browser-bin", "", "Chromium/Chrome binary path (optional)")
+	cmd.Flags().StringVar(&attach, "attach", "", "Attach to an already-running browser (DevTools ws URL or port) instead of launching one")
+	cmd.Flags().BoolVar(&adoptTab, "adopt-tab", false, "With --attach, reuse the browser's first tab instead of opening a new one")
+	cmd.Flags().StringVar(&routesFile, "routes", "", "Load mock routes from a JSON file (format: see canvas route --help)")
 	return cmd
 }
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+type sessionInfo struct {
+	Name       string `json:"name"`
+	StateDir   string `json:"state_dir"`
+	Running    bool   `json:"running"`
+	PID        int    `json:"pid,omitempty"`
+	Dir        string `json:"dir,omitempty"`
+	HTTPPort   int    `json:"http_port,omitempty"`
+	CurrentURL string `json:"current_url,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newSessionsCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List canvas sessions and their status",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessions, err := state.ListSessions()
+			if err != nil {
+				return err
+			}
+
+			out := make([]sessionInfo, 0, len(sessions))
+			for _, sd := range sessions {
+				out = append(out, probeSession(sd))
+			}
+
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			if len(out) == 0 {
+				fmt.Fprintln(os.Stdout, "no sessions")
+				return nil
+			}
+			for _, s := range out {
+				if !s.Running {
+					fmt.Fprintf(os.Stdout, "%s\tstale\t%s\n", s.Name, s.StateDir)
+					continue
+				}
+				fmt.Fprintf(os.Stdout, "%s\trunning\thttp://127.0.0.1:%d/\t%s\n", s.Name, s.HTTPPort, s.Dir)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func probeSession(sd state.SessionDir) sessionInfo {
+	info := sessionInfo{Name: sd.Name, StateDir: sd.StateDir}
+	s, err := state.Load(sd.StateDir)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.PID = s.PID
+	info.Dir = s.Dir
+
+	c := rpc.NewUnixClient(s.SocketPath, s.Token)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	st, err := c.Status(ctx)
+	cancel()
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Running = st.Running
+	info.HTTPPort = st.HTTPPort
+	info.CurrentURL = st.CurrentURL
+	return info
+}
+
+func sessionOrDefault(name string) string {
+	if name == "" {
+		return state.DefaultSession
+	}
+	return name
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestSessionFlag_UsesSessionStateDir(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", base)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/goto", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(rpc.GotoResponse{URL: "http://127.0.0.1:1/agent"})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(filepath.Join(base, "sessions", "agent"), state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Default session has no session file => error.
+	if err := newGotoCmd(&rootFlags{}).RunE(nil, []string{"/"}); err == nil {
+		t.Fatal("expected default session to be missing")
+	}
+
+	t.Setenv("CANVAS_SESSION", "agent")
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := newGotoCmd(&rootFlags{})
+	cmd.SetArgs([]string{"/agent"})
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+	if buf.String() != "http://127.0.0.1:1/agent\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestSessionsCommand_ListsRunningAndStale(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", base)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(rpc.StatusResponse{Running: true, HTTPPort: 4242, Dir: "/tmp/a"})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(base, state.Session{PID: 1, SocketPath: socketPath, Token: "token123", Dir: "/tmp/a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(filepath.Join(base, "sessions", "old"), state.Session{PID: 2, SocketPath: filepath.Join(base, "missing.sock")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newSessionsCmd(&rootFlags{jsonOutput: true}).Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	var out []sessionInfo
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json: %v output=%q", err, buf.String())
+	}
+	if len(out) != 2 {
+		t.Fatalf("sessions = %#v", out)
+	}
+	if out[0].Name != "default" || !out[0].Running || out[0].HTTPPort != 4242 {
+		t.Fatalf("default = %#v", out[0])
+	}
+	if out[1].Name != "old" || out[1].Running {
+		t.Fatalf("old = %#v", out[1])
+	}
+}
+
+func TestStopCommand_All(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", base)
+
+	var stops []string
+	newServer := func(name string) string {
+		socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+			mux.HandleFunc("/stop", func(w http.ResponseWriter, r *http.Request) {
+				stops = append(stops, name)
+				_ = json.NewEncoder(w).Encode(rpc.StopResponse{OK: true})
+			})
+		})
+		t.Cleanup(shutdown)
+		return socketPath
+	}
+
+	agentDir := filepath.Join(base, "sessions", "agent")
+	if err := state.Save(base, state.Session{PID: 1, SocketPath: newServer("default"), Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(agentDir, state.Session{PID: 2, SocketPath: newServer("agent"), Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newStopCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--all"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	if strings.Join(stops, ",") != "default,agent" {
+		t.Fatalf("stops = %v", stops)
+	}
+	if sessions, _ := state.ListSessions(); len(sessions) != 0 {
+		t.Fatalf("sessions left: %#v", sessions)
+	}
+}
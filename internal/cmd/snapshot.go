@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/imgdiff"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+const (
+	defaultBaselineDir = ".canvas/baselines"
+	defaultDiffDir     = ".canvas/diffs"
+)
+
+// snapshotFlags are shared by the snapshot subcommands.
+type snapshotFlags struct {
+	dir      string
+	diffDir  string
+	selector string
+	fullPage bool
+	clip     string
+}
+
+func (f *snapshotFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.dir, "dir", defaultBaselineDir, "Directory of baseline PNGs")
+	cmd.Flags().StringVar(&f.diffDir, "diff-dir", defaultDiffDir, "Directory for the actual and diff PNGs of mismatches")
+	cmd.Flags().StringVar(&f.selector, "selector", "", "Capture only this element")
+	cmd.Flags().BoolVar(&f.fullPage, "full-page", false, "Capture the whole page, beyond the viewport")
+	cmd.Flags().StringVar(&f.clip, "clip", "", "Capture a region: x,y,width,height in CSS pixels")
+}
+
+func (f *snapshotFlags) request() (rpc.ScreenshotRequest, error) {
+	req := rpc.ScreenshotRequest{Format: "png", Selector: f.selector, FullPage: f.fullPage}
+	if f.clip != "" {
+		r, err := parseClip(f.clip)
+		if err != nil {
+			return req, err
+		}
+		req.Clip = r
+	}
+	return req, nil
+}
+
+// paths returns the baseline, actual and diff PNG of a snapshot.
+func (f *snapshotFlags) paths(name string) (baseline, actual, diff string) {
+	name = filepath.FromSlash(name)
+	return filepath.Join(f.dir, name+".png"),
+		filepath.Join(f.diffDir, name+".actual.png"),
+		filepath.Join(f.diffDir, name+".diff.png")
+}
+
+func checkSnapshotName(name string) error {
+	if name == "" || !filepath.IsLocal(filepath.FromSlash(name)) || strings.HasSuffix(name, ".png") {
+		return fmt.Errorf("invalid snapshot name %q (use a relative name like home or mobile/nav, without .png)", name)
+	}
+	return nil
+}
+
+func newSnapshotCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Visual regression checks against baseline screenshots",
+		Long: `Compare screenshots of the tab with baseline PNGs (default dir: .canvas/baselines).
+
+The first "snapshot compare <name>" saves the baseline. Later runs compare
+pixel by pixel and exit non-zero on a mismatch, writing <name>.actual.png and
+a highlighted <name>.diff.png (red: differences, yellow: ignored
+anti-aliasing) to .canvas/diffs. "snapshot update" accepts them as the new
+baselines.`,
+	}
+	cmd.AddCommand(newSnapshotCompareCmd(root), newSnapshotUpdateCmd(root))
+	return cmd
+}
+
+// snapshotResult is the --json output of snapshot compare.
+type snapshotResult struct {
+	Name        string  `json:"name"`
+	Status      string  `json:"status"` // new, match or mismatch
+	Baseline    string  `json:"baseline"`
+	Actual      string  `json:"actual,omitempty"`
+	Diff        string  `json:"diff,omitempty"`
+	DiffPixels  int     `json:"diff_pixels"`
+	DiffRatio   float64 `json:"diff_ratio"`
+	AntiAliased int     `json:"anti_aliased_pixels"`
+	SizeChanged bool    `json:"size_changed,omitempty"`
+}
+
+func newSnapshotCompareCmd(root *rootFlags) *cobra.Command {
+	var (
+		flags     snapshotFlags
+		threshold float64
+		maxDiff   string
+		includeAA bool
+	)
+	cmd := &cobra.Command{
+		Use:   "compare <name>",
+		Short: "Compare the tab with a baseline (saving it on the first run)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := checkSnapshotName(name); err != nil {
+				return err
+			}
+			if threshold < 0 || threshold > 1 {
+				return errors.New("--threshold must be between 0 and 1")
+			}
+			allowed, err := parseMaxDiff(maxDiff)
+			if err != nil {
+				return err
+			}
+			req, err := flags.request()
+			if err != nil {
+				return err
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			shot, err := captureSnapshot(c.WithTab(root.tab), req)
+			if err != nil {
+				return err
+			}
+
+			baselinePath, actualPath, diffPath := flags.paths(name)
+			res := snapshotResult{Name: name, Baseline: baselinePath}
+			baselineData, err := os.ReadFile(baselinePath)
+			if errors.Is(err, fs.ErrNotExist) {
+				if err := writeFileMkdir(baselinePath, shot); err != nil {
+					return err
+				}
+				res.Status = "new"
+				if root.jsonOutput {
+					return printJSON(res)
+				}
+				fmt.Fprintf(os.Stdout, "saved new baseline %s\n", baselinePath)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			baseline, err := png.Decode(bytes.NewReader(baselineData))
+			if err != nil {
+				return fmt.Errorf("%s: %w", baselinePath, err)
+			}
+			actual, err := png.Decode(bytes.NewReader(shot))
+			if err != nil {
+				return fmt.Errorf("screenshot: %w", err)
+			}
+			diff := imgdiff.Compare(baseline, actual, imgdiff.Options{Threshold: threshold, IncludeAA: includeAA})
+			res.DiffPixels, res.DiffRatio, res.AntiAliased, res.SizeChanged = diff.Diff, diff.Ratio(), diff.AntiAliased, diff.SizeMismatch
+
+			if !diff.SizeMismatch && allowed.allows(diff.Diff, diff.Ratio()) {
+				// A pass clears what an earlier mismatch left behind.
+				_ = os.Remove(actualPath)
+				_ = os.Remove(diffPath)
+				res.Status = "match"
+				if root.jsonOutput {
+					return printJSON(res)
+				}
+				fmt.Fprintf(os.Stdout, "%s matches (%d pixels differ, %d anti-aliased)\n", name, diff.Diff, diff.AntiAliased)
+				return nil
+			}
+
+			var diffPNG bytes.Buffer
+			if err := png.Encode(&diffPNG, diff.Image); err != nil {
+				return err
+			}
+			if err := writeFileMkdir(actualPath, shot); err != nil {
+				return err
+			}
+			if err := writeFileMkdir(diffPath, diffPNG.Bytes()); err != nil {
+				return err
+			}
+			res.Status, res.Actual, res.Diff = "mismatch", actualPath, diffPath
+			if root.jsonOutput {
+				if err := printJSON(res); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(os.Stdout, "actual: %s\ndiff:   %s\n", actualPath, diffPath)
+			}
+			if diff.SizeMismatch {
+				b := baseline.Bounds()
+				a := actual.Bounds()
+				return fmt.Errorf("snapshot %s differs: size changed from %dx%d to %dx%d", name, b.Dx(), b.Dy(), a.Dx(), a.Dy())
+			}
+			return fmt.Errorf("snapshot %s differs: %d pixels (%.2f%%)", name, diff.Diff, diff.Ratio()*100)
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().Float64Var(&threshold, "threshold", 0.1, "Color distance (0-1) under which pixels count as equal")
+	cmd.Flags().StringVar(&maxDiff, "max-diff", "0", "Differing pixels to tolerate: a count like 50, or a share like 0.1%")
+	cmd.Flags().BoolVar(&includeAA, "include-aa", false, "Count anti-aliased pixels as differences")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func newSnapshotUpdateCmd(root *rootFlags) *cobra.Command {
+	var flags snapshotFlags
+	cmd := &cobra.Command{
+		Use:   "update [name...]",
+		Short: "Accept new baselines",
+		Long: `Accept new baselines: the actual PNG of the last failed compare of each name,
+or a fresh capture of the tab if there is none. Without names, accept every
+pending mismatch in the diff dir.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := args
+			if len(names) == 0 {
+				pending, err := pendingSnapshots(flags.diffDir)
+				if err != nil {
+					return err
+				}
+				if len(pending) == 0 {
+					return errors.New("no pending mismatches; name the snapshots to capture")
+				}
+				names = pending
+			}
+			for _, name := range names {
+				if err := checkSnapshotName(name); err != nil {
+					return err
+				}
+			}
+
+			for _, name := range names {
+				baselinePath, actualPath, diffPath := flags.paths(name)
+				shot, err := os.ReadFile(actualPath)
+				if errors.Is(err, fs.ErrNotExist) {
+					req, rerr := flags.request()
+					if rerr != nil {
+						return rerr
+					}
+					c, _, _, cerr := mustClient(root)
+					if cerr != nil {
+						return cerr
+					}
+					shot, err = captureSnapshot(c.WithTab(root.tab), req)
+				}
+				if err != nil {
+					return err
+				}
+				if err := writeFileMkdir(baselinePath, shot); err != nil {
+					return err
+				}
+				_ = os.Remove(actualPath)
+				_ = os.Remove(diffPath)
+				if !root.jsonOutput {
+					fmt.Fprintf(os.Stdout, "updated %s\n", baselinePath)
+				}
+			}
+			if root.jsonOutput {
+				return printJSON(map[string]any{"updated": names})
+			}
+			return nil
+		},
+	}
+	flags.register(cmd)
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+// pendingSnapshots lists the names with an actual PNG in the diff dir.
+func pendingSnapshots(diffDir string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(diffDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if name, ok := strings.CutSuffix(path, ".actual.png"); ok && !d.IsDir() {
+			rel, err := filepath.Rel(diffDir, name)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return names, err
+}
+
+func captureSnapshot(c *rpc.Client, req rpc.ScreenshotRequest) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	var buf bytes.Buffer
+	if err := screenshotTo(ctx, c.WithTimeout(2*time.Minute), req, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeFileMkdir(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// maxDiff is the tolerated difference: a pixel count, or a ratio if
+// isRatio.
+type maxDiff struct {
+	value   float64
+	isRatio bool
+}
+
+func (m maxDiff) allows(pixels int, ratio float64) bool {
+	if m.isRatio {
+		return ratio <= m.value
+	}
+	return float64(pixels) <= m.value
+}
+
+func parseMaxDiff(s string) (maxDiff, error) {
+	s = strings.TrimSpace(s)
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || v < 0 || v > 100 {
+			return maxDiff{}, fmt.Errorf("invalid --max-diff %q", s)
+		}
+		return maxDiff{value: v / 100, isRatio: true}, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return maxDiff{}, fmt.Errorf("invalid --max-diff %q (want a pixel count or a percentage)", s)
+	}
+	return maxDiff{value: float64(v)}, nil
+}
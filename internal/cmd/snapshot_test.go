@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steipete/canvas/internal/state"
+)
+
+func solidPNG(t *testing.T, w, h int, c color.Color, marks ...image.Point) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, c)
+		}
+	}
+	for _, p := range marks {
+		img.Set(p.X, p.Y, color.Black)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSnapshotCommands(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var (
+		mu   sync.Mutex
+		shot []byte
+	)
+	setShot := func(b []byte) {
+		mu.Lock()
+		shot = b
+		mu.Unlock()
+	}
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/screenshot/raw", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(shot)
+		})
+	})
+	t.Cleanup(shutdown)
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	baselines, diffs := filepath.Join(dir, "baselines"), filepath.Join(dir, "diffs")
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		cmd := newSnapshotCmd(&rootFlags{})
+		cmd.SetArgs(append(args, "--dir", baselines, "--diff-dir", diffs))
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		var buf bytes.Buffer
+		restore, err := captureStdout(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = cmd.Execute()
+		_ = restore()
+		return buf.String(), err
+	}
+
+	white := color.NRGBA{255, 255, 255, 255}
+	base := solidPNG(t, 20, 10, white)
+	setShot(base)
+	if out, err := run("compare", "home"); err != nil || !strings.Contains(out, "saved new baseline") {
+		t.Fatalf("first compare = %q, %v", out, err)
+	}
+	if b, err := os.ReadFile(filepath.Join(baselines, "home.png")); err != nil || !bytes.Equal(b, base) {
+		t.Fatalf("baseline = %d bytes, %v", len(b), err)
+	}
+	if out, err := run("compare", "home"); err != nil || !strings.Contains(out, "home matches") {
+		t.Fatalf("second compare = %q, %v", out, err)
+	}
+
+	changed := solidPNG(t, 20, 10, white, image.Pt(3, 3), image.Pt(15, 7))
+	setShot(changed)
+	if _, err := run("compare", "home"); err == nil || !strings.Contains(err.Error(), "2 pixels") {
+		t.Fatalf("mismatch compare error = %v", err)
+	}
+	actualPath, diffPath := filepath.Join(diffs, "home.actual.png"), filepath.Join(diffs, "home.diff.png")
+	f, err := os.Open(diffPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffImg, err := png.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, _ := diffImg.At(3, 3).RGBA(); r>>8 != 255 || g != 0 || b != 0 {
+		t.Fatalf("diff pixel = %v, want red", diffImg.At(3, 3))
+	}
+	if _, err := run("compare", "home", "--max-diff", "2"); err != nil {
+		t.Fatalf("compare within --max-diff: %v", err)
+	}
+	if _, err := os.Stat(actualPath); !os.IsNotExist(err) {
+		t.Fatalf("stale actual left behind: %v", err)
+	}
+
+	if _, err := run("compare", "home"); err == nil {
+		t.Fatal("expected a mismatch")
+	}
+	if out, err := run("update"); err != nil || !strings.Contains(out, "updated") {
+		t.Fatalf("update = %q, %v", out, err)
+	}
+	if b, err := os.ReadFile(filepath.Join(baselines, "home.png")); err != nil || !bytes.Equal(b, changed) {
+		t.Fatalf("updated baseline = %d bytes, %v", len(b), err)
+	}
+	if _, err := os.Stat(diffPath); !os.IsNotExist(err) {
+		t.Fatalf("diff left behind after update: %v", err)
+	}
+
+	setShot(solidPNG(t, 20, 12, white))
+	if _, err := run("compare", "home"); err == nil || !strings.Contains(err.Error(), "size changed from 20x10 to 20x12") {
+		t.Fatalf("size change error = %v", err)
+	}
+	if _, err := run("compare", "../escape"); err == nil {
+		t.Fatal("expected an invalid name error")
+	}
+}
+
+func TestParseMaxDiff(t *testing.T) {
+	if m, err := parseMaxDiff("0.5%"); err != nil || !m.isRatio || m.value != 0.005 {
+		t.Fatalf("parseMaxDiff(0.5%%) = %+v, %v", m, err)
+	}
+	if m, err := parseMaxDiff("40"); err != nil || m.isRatio || !m.allows(40, 1) || m.allows(41, 0) {
+		t.Fatalf("parseMaxDiff(40) = %+v, %v", m, err)
+	}
+	for _, in := range []string{"", "-1", "1.5", "x%", "101%"} {
+		if _, err := parseMaxDiff(in); err == nil {
+			t.Errorf("parseMaxDiff(%q): expected an error", in)
+		}
+	}
+}
@@ -24,7 +24,10 @@ func newStartCmd(root *rootFlags) *cobra.Command {
 		app          bool
 		windowSize   string
 		browserBin   string
+		attach       string
+		adoptTab     bool
 		stealth      bool
+		routesFile   string
 		restart      bool
 	)
 
@@ -32,7 +35,7 @@ func newStartCmd(root *rootFlags) *cobra.Command {
 		Use:   "start",
 		Short: "Start canvas in the background (daemon)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			stateDir, err := state.DefaultStateDir()
+			stateDir, err := root.stateDir()
 			if err != nil {
 				return err
 			}
@@ -80,6 +83,14 @@ func newStartCmd(root *rootFlags) *cobra.Command {
 				}
 			}
 
+			if routesFile != "" {
+				abs, err := checkRoutesFile(routesFile)
+				if err != nil {
+					return err
+				}
+				routesFile = abs
+			}
+
 			tempDir := false
 			if dir == "" {
 				d, err := os.MkdirTemp("", "canvas-*")
@@ -107,6 +118,7 @@ func newStartCmd(root *rootFlags) *cobra.Command {
 
 			args2 := []string{
 				"daemon",
+				"--session", sessionOrDefault(root.sessionName()),
 				"--state-dir", stateDir,
 				"--dir", dir,
 				"--port", fmt.Sprintf("%d", port),
@@ -121,9 +133,18 @@ func newStartCmd(root *rootFlags) *cobra.Command {
 			if browserBin != "" {
 				args2 = append(args2, "--browser-bin", browserBin)
 			}
+			if attach != "" {
+				args2 = append(args2, "--attach", attach)
+				if adoptTab {
+					args2 = append(args2, "--adopt-tab")
+				}
+			}
 			if tempDir {
 				args2 = append(args2, "--temp-dir")
 			}
+			if routesFile != "" {
+				args2 = append(args2, "--routes", routesFile)
+			}
 
 			if err := spawnDaemon(os.Args[0], args2, logFile); err != nil {
 				return err
@@ -161,6 +182,9 @@ func newStartCmd(root *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&stealth, "stealth", true, "Best-effort automation detection reduction")
 	cmd.Flags().StringVar(&windowSize, "window-size", "1280,720", "Browser window size, e.g. 1280,720")
 	cmd.Flags().StringVar(&browserBin, "browser-bin", "", "Chromium/Chrome binary path (optional)")
+	cmd.Flags().StringVar(&attach, "attach", "", "Attach to an already-running browser (DevTools ws URL or port) instead of launching one")
+	cmd.Flags().BoolVar(&adoptTab, "adopt-tab", false, "With --attach, reuse the browser's first tab instead of opening a new one")
+	cmd.Flags().StringVar(&routesFile, "routes", "", "Load mock routes from a JSON file (format: see canvas route --help)")
 	cmd.Flags().BoolVar(&restart, "restart", false, "Restart if already running")
 
 	return cmd
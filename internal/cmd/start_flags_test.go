@@ -1,6 +1,13 @@
 package cmd
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestStartCmd_StealthFlagDefault(t *testing.T) {
 	flags := &rootFlags{}
@@ -13,3 +20,63 @@ func TestStartCmd_StealthFlagDefault(t *testing.T) {
 		t.Fatalf("expected --stealth default true")
 	}
 }
+
+func TestStartCmd_AttachPassedToDaemon(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var gotArgs []string
+	oldSpawn := spawnDaemon
+	t.Cleanup(func() { spawnDaemon = oldSpawn })
+	spawnDaemon = func(bin string, args []string, logFile *os.File) error {
+		gotArgs = args
+		return errors.New("spawn disabled in test")
+	}
+
+	cmd := newStartCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--attach", "9222", "--adopt-tab", "--dir", t.TempDir()})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected spawn error")
+	}
+
+	joined := strings.Join(gotArgs, " ")
+	if !strings.Contains(joined, "--attach 9222") || !slices.Contains(gotArgs, "--adopt-tab") {
+		t.Fatalf("daemon args missing attach flags: %v", gotArgs)
+	}
+}
+
+func TestStartCmd_RoutesPassedToDaemon(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var gotArgs []string
+	oldSpawn := spawnDaemon
+	t.Cleanup(func() { spawnDaemon = oldSpawn })
+	spawnDaemon = func(bin string, args []string, logFile *os.File) error {
+		gotArgs = args
+		return errors.New("spawn disabled in test")
+	}
+
+	routes := filepath.Join(t.TempDir(), "routes.json")
+	if err := os.WriteFile(routes, []byte(`[{"pattern": "*/api/*", "status": 204}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newStartCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--routes", routes, "--dir", t.TempDir()})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected spawn error")
+	}
+	if !strings.Contains(strings.Join(gotArgs, " "), "--routes "+routes) {
+		t.Fatalf("daemon args missing --routes: %v", gotArgs)
+	}
+
+	if err := os.WriteFile(routes, []byte(`[{"pattern": "*", "fail": "Nope"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gotArgs = nil
+	cmd = newStartCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--routes", routes, "--dir", t.TempDir()})
+	if err := cmd.Execute(); err == nil || gotArgs != nil {
+		t.Fatalf("expected an invalid routes file to fail before spawning, got %v", err)
+	}
+}
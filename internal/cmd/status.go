@@ -17,7 +17,7 @@ func newStatusCmd(root *rootFlags) *cobra.Command {
 		Use:   "status",
 		Short: "Show current canvas session status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			stateDir, err := state.DefaultStateDir()
+			stateDir, err := root.stateDir()
 			if err != nil {
 				return err
 			}
@@ -48,11 +48,43 @@ func newStatusCmd(root *rootFlags) *cobra.Command {
 			fmt.Fprintf(os.Stdout, "running: http://%s:%d/\n", st.HTTPAddr, st.HTTPPort)
 			fmt.Fprintf(os.Stdout, "dir: %s\n", st.Dir)
 			fmt.Fprintf(os.Stdout, "url: %s\n", st.CurrentURL)
+			if st.Attached {
+				fmt.Fprintln(os.Stdout, "browser: attached")
+			}
+			if len(st.Tabs) > 1 {
+				fmt.Fprintf(os.Stdout, "tabs: %d (active: %s)\n", len(st.Tabs), st.ActiveTab)
+			}
+			if st.Emulation != nil {
+				for _, l := range emulationLines(*st.Emulation) {
+					fmt.Fprintln(os.Stdout, l)
+				}
+			}
+			if st.HARRecording {
+				fmt.Fprintln(os.Stdout, "har: recording")
+			}
+			if st.HARReplay > 0 {
+				fmt.Fprintf(os.Stdout, "har: replaying %d entries\n", st.HARReplay)
+			}
+			if st.Tracing != "" {
+				fmt.Fprintf(os.Stdout, "tracing: tab %s\n", st.Tracing)
+			}
+			if st.Coverage != "" {
+				fmt.Fprintf(os.Stdout, "coverage: tab %s\n", st.Coverage)
+			}
+			if st.Screencast != "" {
+				fmt.Fprintf(os.Stdout, "recording: tab %s\n", st.Screencast)
+			}
+			if st.Routes > 0 {
+				fmt.Fprintf(os.Stdout, "routes: %d\n", st.Routes)
+			}
 			if st.DevToolsWSURL != "" {
 				fmt.Fprintf(os.Stdout, "devtools: %s\n", st.DevToolsWSURL)
 			} else if st.DevToolsPort != 0 {
 				fmt.Fprintf(os.Stdout, "devtools-port: %d\n", st.DevToolsPort)
 			}
+			if st.Restarts > 0 || st.LastCrash != "" {
+				fmt.Fprintf(os.Stdout, "browser restarts: %d (last crash: %s at %s)\n", st.Restarts, st.LastCrash, st.LastCrashAt.Format(time.RFC3339))
+			}
 			return nil
 		},
 	}
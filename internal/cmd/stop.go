@@ -8,16 +8,23 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/steipete/canvas/internal/rpc"
 	"github.com/steipete/canvas/internal/state"
 )
 
 func newStopCmd(root *rootFlags) *cobra.Command {
+	var all bool
+
 	cmd := &cobra.Command{
 		Use:     "stop",
 		Aliases: []string{"close"},
 		Short:   "Stop canvas (server + controlled browser)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, _, stateDir, err := mustClient()
+			if all {
+				return runStopAll(root)
+			}
+
+			c, _, stateDir, err := mustClient(root)
 			if err != nil {
 				// Not running.
 				if root.jsonOutput {
@@ -39,5 +46,39 @@ func newStopCmd(root *rootFlags) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Stop every session")
 	return cmd
 }
+
+func runStopAll(root *rootFlags) error {
+	sessions, err := state.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	stopped := []string{}
+	for _, sd := range sessions {
+		s, err := state.Load(sd.StateDir)
+		if err == nil && s.SocketPath != "" {
+			c := rpc.NewUnixClient(s.SocketPath, s.Token)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, _ = c.Stop(ctx)
+			cancel()
+		}
+		_ = state.Remove(sd.StateDir)
+		stopped = append(stopped, sd.Name)
+	}
+
+	if root.jsonOutput {
+		return printJSON(map[string]any{"ok": true, "stopped": stopped})
+	}
+	if len(stopped) == 0 {
+		fmt.Fprintln(os.Stdout, "not running")
+		return nil
+	}
+	for _, name := range stopped {
+		fmt.Fprintf(os.Stdout, "stopped %s\n", name)
+	}
+	return nil
+}
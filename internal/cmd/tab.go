@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func newTabCmd(root *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "tab [command]",
+		Aliases: []string{"tabs"},
+		Short:   "Manage tabs (list, new, switch, close)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTabList(root)
+		},
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List open tabs",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runTabList(root)
+			},
+		},
+		&cobra.Command{
+			Use:   "new [path-or-url]",
+			Short: "Open a new tab and make it active",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				c, _, _, err := mustClient(root)
+				if err != nil {
+					return err
+				}
+				url := ""
+				if len(args) == 1 {
+					url = args[0]
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				out, err := c.TabNew(ctx, url)
+				cancel()
+				if err != nil {
+					return err
+				}
+				if root.jsonOutput {
+					return printJSON(out)
+				}
+				fmt.Fprintln(os.Stdout, out.Tab.ID)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "switch <id>",
+			Short: "Make a tab active (ID or unique prefix)",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				c, _, _, err := mustClient(root)
+				if err != nil {
+					return err
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+				out, err := c.TabSwitch(ctx, args[0])
+				cancel()
+				if err != nil {
+					return err
+				}
+				if root.jsonOutput {
+					return printJSON(out)
+				}
+				fmt.Fprintf(os.Stdout, "%s %s\n", out.Tab.ID, out.Tab.URL)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "close [id]",
+			Short: "Close a tab (default: the active tab)",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				c, _, _, err := mustClient(root)
+				if err != nil {
+					return err
+				}
+				id := ""
+				if len(args) == 1 {
+					id = args[0]
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+				out, err := c.TabClose(ctx, id)
+				cancel()
+				if err != nil {
+					return err
+				}
+				if root.jsonOutput {
+					return printJSON(out)
+				}
+				printTabs(out.Tabs)
+				return nil
+			},
+		},
+	)
+
+	return cmd
+}
+
+func runTabList(root *rootFlags) error {
+	c, _, _, err := mustClient(root)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	out, err := c.Tabs(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if root.jsonOutput {
+		return printJSON(out)
+	}
+	printTabs(out.Tabs)
+	return nil
+}
+
+func printTabs(tabs []rpc.TabInfo) {
+	for _, t := range tabs {
+		marker := " "
+		if t.Active {
+			marker = "*"
+		}
+		fmt.Fprintf(os.Stdout, "%s %s %s", marker, t.ID, t.URL)
+		if t.Title != "" {
+			fmt.Fprintf(os.Stdout, " (%s)", t.Title)
+		}
+		fmt.Fprintln(os.Stdout)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestTabListCommand_Text(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/tabs", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(rpc.TabsResponse{Tabs: []rpc.TabInfo{
+				{ID: "AAA", URL: "http://127.0.0.1:1/", Title: "App", Active: true},
+				{ID: "BBB", URL: "http://127.0.0.1:1/docs"},
+			}})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newTabCmd(&rootFlags{})
+	cmd.SetArgs([]string{"list"})
+
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	want := "* AAA http://127.0.0.1:1/ (App)\n  BBB http://127.0.0.1:1/docs\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestGotoCommand_TabFlagSetsHeader(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var gotTab string
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/goto", func(w http.ResponseWriter, r *http.Request) {
+			gotTab = r.Header.Get(rpc.TabHeader)
+			_ = json.NewEncoder(w).Encode(rpc.GotoResponse{URL: "http://127.0.0.1:1/docs"})
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newGotoCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--tab", "BBB", "/docs"})
+
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Execute(); err != nil {
+		_ = restore()
+		t.Fatal(err)
+	}
+	_ = restore()
+
+	if gotTab != "BBB" {
+		t.Fatalf("tab header = %q", gotTab)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+// runShell runs the --during action; tests replace it.
+var runShell = func(command string, env []string) error {
+	proc := exec.Command("sh", "-c", command)
+	proc.Env = env
+	proc.Stdin = os.Stdin
+	proc.Stdout = os.Stderr // keep stdout for the trace when --out is omitted
+	proc.Stderr = os.Stderr
+	return proc.Run()
+}
+
+func newTraceCmd(root *rootFlags) *cobra.Command {
+	var (
+		during     string
+		outPath    string
+		categories []string
+		settle     time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Capture Chrome performance traces",
+		Long: `Capture a Chrome trace (Tracing domain) of the tab. The trace is trace event
+JSON that opens in Perfetto (ui.perfetto.dev) or chrome://tracing.
+
+Use "trace start" / "trace stop --out trace.json", or record a single action:
+
+  canvas trace --during "canvas dom click '#btn'" --out click.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if during == "" {
+				return cmd.Help()
+			}
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			_, err = c.WithTab(root.tab).TraceStart(ctx, categories)
+			cancel()
+			if err != nil {
+				return err
+			}
+
+			env := os.Environ()
+			if s := root.sessionName(); s != "" {
+				env = append(env, "CANVAS_SESSION="+s)
+			}
+			actionErr := runShell(during, env)
+			if actionErr == nil && settle > 0 {
+				time.Sleep(settle)
+			}
+			if err := writeTrace(c, outPath); err != nil {
+				return err
+			}
+			if actionErr != nil {
+				return fmt.Errorf("--during: %w", actionErr)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&during, "during", "", "Shell command to trace (trace starts before and stops after it)")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the trace to this file (default: stdout)")
+	cmd.Flags().StringSliceVar(&categories, "categories", nil, "Trace categories (default: the DevTools performance panel's; prefix - to exclude)")
+	cmd.Flags().DurationVar(&settle, "settle", 500*time.Millisecond, "Keep tracing this long after the --during command, to catch the frames it caused")
+	addTabFlag(cmd, root)
+	cmd.AddCommand(newTraceStartCmd(root), newTraceStopCmd(root))
+	return cmd
+}
+
+func newTraceStartCmd(root *rootFlags) *cobra.Command {
+	var categories []string
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start tracing the tab",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			out, err := c.WithTab(root.tab).TraceStart(ctx, categories)
+			cancel()
+			if err != nil {
+				return err
+			}
+			if root.jsonOutput {
+				return printJSON(out)
+			}
+			fmt.Fprintf(os.Stdout, "tracing tab %s (stop with `canvas trace stop --out trace.json`)\n", out.Tab)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&categories, "categories", nil, "Trace categories (default: the DevTools performance panel's; prefix - to exclude)")
+	addTabFlag(cmd, root)
+	return cmd
+}
+
+func newTraceStopCmd(root *rootFlags) *cobra.Command {
+	var outPath string
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop tracing and write the trace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, _, err := mustClient(root)
+			if err != nil {
+				return err
+			}
+			return writeTrace(c, outPath)
+		},
+	}
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Write the trace to this file (default: stdout)")
+	return cmd
+}
+
+// writeTrace stops the trace and streams it to path (stdout if empty).
+func writeTrace(c *rpc.Client, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	c = c.WithTimeout(5 * time.Minute)
+
+	if path == "" {
+		return c.TraceStop(ctx, os.Stdout)
+	}
+	n, err := writeFileFrom(path, func(w io.Writer) error { return c.TraceStop(ctx, w) })
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "wrote %s (%s)\n", path, formatBytes(n))
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/state"
+)
+
+func TestTraceDuring(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	var calls []string
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/trace/start", func(w http.ResponseWriter, r *http.Request) {
+			var req rpc.TraceStartRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			calls = append(calls, "start "+req.Categories[0])
+			_ = json.NewEncoder(w).Encode(rpc.TraceStartResponse{OK: true, Tab: "T1"})
+		})
+		mux.HandleFunc("/trace/stop", func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "stop")
+			_, _ = w.Write([]byte(`{"traceEvents":[]}`))
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	oldRun := runShell
+	t.Cleanup(func() { runShell = oldRun })
+	runShell = func(command string, env []string) error {
+		calls = append(calls, "run "+command)
+		return nil
+	}
+
+	out := filepath.Join(t.TempDir(), "click.json")
+	cmd := newTraceCmd(&rootFlags{})
+	cmd.SetArgs([]string{"--during", "canvas dom click '#btn'", "--out", out, "--categories", "devtools.timeline", "--settle", "0"})
+	var buf bytes.Buffer
+	restore, err := captureStdout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Execute()
+	_ = restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"start devtools.timeline", "run canvas dom click '#btn'", "stop"}
+	if len(calls) != 3 || calls[0] != want[0] || calls[1] != want[1] || calls[2] != want[2] {
+		t.Fatalf("calls = %q", calls)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != `{"traceEvents":[]}` {
+		t.Fatalf("trace = %q, %v", data, err)
+	}
+}
+
+func TestTraceStopErrorRemovesFile(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", stateDir)
+
+	socketPath, shutdown := startUnixRPCServer(t, "token123", func(mux *http.ServeMux) {
+		mux.HandleFunc("/trace/stop", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "no trace running", http.StatusInternalServerError)
+		})
+	})
+	t.Cleanup(shutdown)
+
+	if err := state.Save(stateDir, state.Session{PID: 1, SocketPath: socketPath, Token: "token123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "trace.json")
+	cmd := newTraceCmd(&rootFlags{})
+	cmd.SetArgs([]string{"stop", "--out", out})
+	err := cmd.Execute()
+	if err == nil || !bytes.Contains([]byte(err.Error()), []byte("no trace running")) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatalf("partial trace left behind: %v", err)
+	}
+}
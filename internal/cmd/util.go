@@ -5,15 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
+	"github.com/spf13/cobra"
+
 	"github.com/steipete/canvas/internal/rpc"
 	"github.com/steipete/canvas/internal/state"
 )
 
-func loadSession() (state.Session, string, error) {
-	stateDir, err := state.DefaultStateDir()
+func loadSession(root *rootFlags) (state.Session, string, error) {
+	stateDir, err := root.stateDir()
 	if err != nil {
 		return state.Session{}, "", err
 	}
@@ -21,14 +24,18 @@ func loadSession() (state.Session, string, error) {
 	return s, stateDir, err
 }
 
-func mustClient() (*rpc.Client, state.Session, string, error) {
-	s, stateDir, err := loadSession()
+func mustClient(root *rootFlags) (*rpc.Client, state.Session, string, error) {
+	s, stateDir, err := loadSession(root)
 	if err != nil {
 		return nil, state.Session{}, stateDir, err
 	}
 	return rpc.NewUnixClient(s.SocketPath, s.Token), s, stateDir, nil
 }
 
+func addTabFlag(cmd *cobra.Command, root *rootFlags) {
+	cmd.Flags().StringVar(&root.tab, "tab", "", "Target a specific tab (ID or unique prefix) without switching")
+}
+
 func printJSON(v any) error {
 	b, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -55,3 +62,36 @@ func awaitDaemonReady(stateDir string, timeout time.Duration) (state.Session, er
 	}
 	return state.Session{}, errors.New("timed out waiting for daemon")
 }
+
+// writeFileFrom streams write's output into path and returns its size; the
+// file is removed if streaming fails or produces nothing.
+func writeFileFrom(path string, write func(io.Writer) error) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	n := &countingWriter{w: f}
+	err = write(n)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n.n == 0 {
+		err = errors.New("no data received")
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return 0, err
+	}
+	return n.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
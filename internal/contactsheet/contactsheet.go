@@ -0,0 +1,81 @@
+// Package contactsheet lays out screenshots side by side, each under a
+// label, in a single image.
+package contactsheet
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Item is a screenshot and its label.
+type Item struct {
+	Label string
+	Image image.Image
+}
+
+const (
+	margin    = 32 // around the sheet
+	gap       = 32 // between screenshots
+	textScale = 4  // pixels per font dot
+	labelGap  = 12 // between a label and its screenshot
+)
+
+var (
+	background = color.NRGBA{240, 240, 240, 255}
+	frame      = color.NRGBA{200, 200, 200, 255}
+	ink        = color.NRGBA{32, 32, 32, 255}
+)
+
+// Compose places the items left to right, top aligned, each framed by a
+// 1px border with its label above. Labels use a small built-in font (see
+// glyphs); other characters are left blank.
+func Compose(items []Item) *image.NRGBA {
+	labelHeight := glyphHeight*textScale + labelGap
+	width, height := 2*margin, 0
+	for i, it := range items {
+		b := it.Image.Bounds()
+		if i > 0 {
+			width += gap
+		}
+		width += max(b.Dx()+2, textWidth(it.Label))
+		height = max(height, b.Dy()+2)
+	}
+	height += 2*margin + labelHeight
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(sheet, sheet.Rect, image.NewUniform(background), image.Point{}, draw.Src)
+	x := margin
+	for _, it := range items {
+		b := it.Image.Bounds()
+		drawText(sheet, x, margin, it.Label)
+		top := margin + labelHeight
+		draw.Draw(sheet, image.Rect(x, top, x+b.Dx()+2, top+b.Dy()+2), image.NewUniform(frame), image.Point{}, draw.Src)
+		draw.Draw(sheet, image.Rect(x+1, top+1, x+1+b.Dx(), top+1+b.Dy()), it.Image, b.Min, draw.Src)
+		x += max(b.Dx()+2, textWidth(it.Label)) + gap
+	}
+	return sheet
+}
+
+func textWidth(s string) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * textScale
+}
+
+func drawText(img *image.NRGBA, x, y int, s string) {
+	for _, r := range s {
+		for row, bits := range glyphs[r] {
+			for col := range glyphWidth {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				dot := image.Rect(x+col*textScale, y+row*textScale, x+(col+1)*textScale, y+(row+1)*textScale)
+				draw.Draw(img, dot, image.NewUniform(ink), image.Point{}, draw.Src)
+			}
+		}
+		x += (glyphWidth + 1) * textScale
+	}
+}
@@ -0,0 +1,62 @@
+package contactsheet
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestCompose(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	sheet := Compose([]Item{
+		{Label: "375px", Image: solid(40, 100, red)},
+		{Label: "1280px", Image: solid(120, 60, blue)},
+	})
+
+	labelHeight := glyphHeight*textScale + labelGap
+	// Labels wider than their image widen the column.
+	wantW := 2*margin + max(42, textWidth("375px")) + gap + max(122, textWidth("1280px"))
+	wantH := 2*margin + labelHeight + 102
+	if b := sheet.Bounds(); b.Dx() != wantW || b.Dy() != wantH {
+		t.Fatalf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), wantW, wantH)
+	}
+
+	top := margin + labelHeight
+	if got := sheet.NRGBAAt(margin, top); got != frame {
+		t.Errorf("frame = %v", got)
+	}
+	if got := sheet.NRGBAAt(margin+1, top+1); got != red {
+		t.Errorf("first image = %v", got)
+	}
+	x2 := margin + max(42, textWidth("375px")) + gap
+	if got := sheet.NRGBAAt(x2+121, top+61); got != frame {
+		t.Errorf("second frame corner = %v", got)
+	}
+	if got := sheet.NRGBAAt(x2+60, top+80); got != background {
+		t.Errorf("below the shorter image = %v, want background", got)
+	}
+
+	// The "3" of the first label starts with a full top row.
+	if got := sheet.NRGBAAt(margin, margin); got != ink {
+		t.Errorf("label = %v, want ink", got)
+	}
+}
+
+func TestGlyphsFitWidth(t *testing.T) {
+	for r, rows := range glyphs {
+		for _, bits := range rows {
+			if bits >= 1<<glyphWidth {
+				t.Errorf("glyph %q is wider than %d dots", r, glyphWidth)
+			}
+		}
+	}
+}
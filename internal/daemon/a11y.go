@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func registerA11yHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/a11y/tree", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.A11yTreeRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		nodes, err := controller.AccessibilityTree(tabContext(r), browser.A11yTreeOptions{
+			Selector:        req.Selector,
+			InterestingOnly: req.InterestingOnly,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out := rpc.A11yTreeResponse{Nodes: a11yNodes(nodes)}
+		if out.Nodes == nil {
+			out.Nodes = []rpc.A11yNode{}
+		}
+		rpcWriteJSON(w, http.StatusOK, out)
+	})
+
+	mux.HandleFunc("/a11y/audit", func(w http.ResponseWriter, r *http.Request) {
+		violations, err := controller.A11yAudit(tabContext(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out := rpc.A11yAuditResponse{Violations: make([]rpc.A11yViolation, len(violations))}
+		for i, v := range violations {
+			out.Violations[i] = rpc.A11yViolation{Rule: v.Rule, Severity: v.Severity, Selector: v.Selector, Message: v.Message, HTML: v.HTML}
+		}
+		rpcWriteJSON(w, http.StatusOK, out)
+	})
+}
+
+func a11yNodes(nodes []*browser.AXNode) []rpc.A11yNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	out := make([]rpc.A11yNode, len(nodes))
+	for i, n := range nodes {
+		out[i] = rpc.A11yNode{
+			Role:        n.Role,
+			Name:        n.Name,
+			Value:       n.Value,
+			Description: n.Description,
+			States:      n.States,
+			Children:    a11yNodes(n.Children),
+		}
+	}
+	return out
+}
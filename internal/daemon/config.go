@@ -1,6 +1,7 @@
 package daemon
 
 type Config struct {
+	Session      string
 	StateDir     string
 	ServeDir     string
 	HTTPPort     int
@@ -9,7 +10,10 @@ type Config struct {
 	App          bool
 	WindowSize   string
 	BrowserBin   string
+	Attach       string
+	AdoptTab     bool
 	Stealth      bool
 	TempDir      bool
 	Watch        bool
+	RoutesFile   string // mock routes to load at startup
 }
@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"cmp"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/web"
+)
+
+func registerCoverageHandlers(mux *http.ServeMux, controller *browser.Controller, static *web.StaticHandler, baseURL string) {
+	mux.HandleFunc("/coverage/start", func(w http.ResponseWriter, r *http.Request) {
+		if err := controller.StartCoverage(tabContext(r)); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		tabID, _ := controller.Covering()
+		rpcWriteJSON(w, http.StatusOK, rpc.CoverageStartResponse{OK: true, Tab: tabID})
+	})
+
+	mux.HandleFunc("/coverage/stop", func(w http.ResponseWriter, r *http.Request) {
+		sources, err := controller.StopCoverage(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, buildCoverageReport(sources, serveDirResolver(static, baseURL), static.Root()))
+	})
+}
+
+// serveDirResolver maps URLs of the static server to paths relative to the
+// serve dir.
+func serveDirResolver(static *web.StaticHandler, baseURL string) func(rawURL string) (string, bool) {
+	base, _ := url.Parse(baseURL)
+	return func(rawURL string) (string, bool) {
+		u, err := url.Parse(rawURL)
+		if err != nil || base == nil || u.Port() != base.Port() {
+			return "", false
+		}
+		if h := u.Hostname(); h != base.Hostname() && h != "localhost" {
+			return "", false
+		}
+		p, ok := static.Resolve(u.Path)
+		if !ok {
+			return "", false
+		}
+		rel, err := filepath.Rel(static.Root(), p)
+		if err != nil {
+			return "", false
+		}
+		return filepath.ToSlash(rel), true
+	}
+}
+
+// buildCoverageReport merges the sources of each resource (a page's inline
+// scripts count together) and maps them to files via resolve.
+func buildCoverageReport(sources []browser.SourceCoverage, resolve func(string) (string, bool), root string) rpc.CoverageReport {
+	type key struct{ url, typ string }
+	type file struct {
+		rpc.CoverageFile
+		covered, seen map[int]bool
+	}
+	files := map[key]*file{}
+	for _, s := range sources {
+		k := key{s.URL, s.Type}
+		f := files[k]
+		if f == nil {
+			f = &file{
+				CoverageFile: rpc.CoverageFile{URL: s.URL, Type: s.Type},
+				covered:      map[int]bool{},
+				seen:         map[int]bool{},
+			}
+			f.Path, _ = resolve(s.URL)
+			files[k] = f
+		}
+		f.Total += s.Len()
+		f.Used += s.UsedLen()
+		covered, uncovered := s.Lines()
+		for _, l := range covered {
+			f.covered[l] = true
+			f.seen[l] = true
+		}
+		for _, l := range uncovered {
+			f.seen[l] = true
+		}
+	}
+
+	out := rpc.CoverageReport{Root: root, Files: []rpc.CoverageFile{}}
+	for _, f := range files {
+		for l := range f.seen {
+			if f.covered[l] {
+				f.Covered = append(f.Covered, l)
+			} else {
+				f.Uncovered = append(f.Uncovered, l)
+			}
+		}
+		slices.Sort(f.Covered)
+		slices.Sort(f.Uncovered)
+		f.Percent = percent(f.Used, f.Total)
+		out.Total += f.Total
+		out.Used += f.Used
+		out.Files = append(out.Files, f.CoverageFile)
+	}
+	out.Percent = percent(out.Used, out.Total)
+	// Files under the serve dir first, then other origins.
+	slices.SortFunc(out.Files, func(a, b rpc.CoverageFile) int {
+		if (a.Path == "") != (b.Path == "") {
+			if a.Path == "" {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.URL, b.URL), cmp.Compare(a.Type, b.Type))
+	})
+	return out
+}
+
+func percent(used, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
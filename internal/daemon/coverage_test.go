@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/web"
+)
+
+func TestServeDirResolver(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "js"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"index.html", filepath.Join("js", "app.js")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	static, err := web.NewStaticHandler(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolve := serveDirResolver(static, "http://127.0.0.1:4321/")
+
+	cases := map[string]string{
+		"http://127.0.0.1:4321/":              "index.html",
+		"http://127.0.0.1:4321/js/app.js?v=3": "js/app.js",
+		"http://localhost:4321/js/app.js":     "js/app.js",
+		"http://127.0.0.1:4321/missing.js":    "",
+		"http://127.0.0.1:9999/js/app.js":     "",
+		"https://cdn.example.com/js/app.js":   "",
+	}
+	for u, want := range cases {
+		got, ok := resolve(u)
+		if ok != (want != "") || got != want {
+			t.Errorf("resolve(%q) = %q, %v; want %q", u, got, ok, want)
+		}
+	}
+}
+
+func TestBuildCoverageReport(t *testing.T) {
+	page := "http://127.0.0.1:4321/"
+	sources := []browser.SourceCoverage{
+		// Two inline scripts of the page.
+		{URL: page, Type: "js", Text: "a();\nb();", StartLine: 3, Used: []browser.Range{{Start: 0, End: 4}}},
+		{URL: page, Type: "js", Text: "c();", StartLine: 9, Used: []browser.Range{{Start: 0, End: 4}}},
+		{URL: page, Type: "css", Text: "p{}\nh1{}", Used: []browser.Range{{Start: 0, End: 3}}},
+		{URL: "https://cdn.example.com/lib.js", Type: "js", Text: "l();", Used: nil},
+	}
+	resolve := func(u string) (string, bool) {
+		if u == page {
+			return "index.html", true
+		}
+		return "", false
+	}
+	rep := buildCoverageReport(sources, resolve, "/srv")
+
+	if rep.Root != "/srv" || rep.Total != 25 || rep.Used != 11 || rep.Percent != 44 {
+		t.Fatalf("report = %+v", rep)
+	}
+	if len(rep.Files) != 3 {
+		t.Fatalf("files = %+v", rep.Files)
+	}
+	css, js, lib := rep.Files[0], rep.Files[1], rep.Files[2]
+	if css.Path != "index.html" || css.Type != "css" || css.Total != 8 || css.Used != 3 ||
+		!reflect.DeepEqual(css.Covered, []int{1}) || !reflect.DeepEqual(css.Uncovered, []int{2}) {
+		t.Fatalf("css = %+v", css)
+	}
+	if js.Path != "index.html" || js.Type != "js" || js.Total != 13 || js.Used != 8 ||
+		!reflect.DeepEqual(js.Covered, []int{4, 10}) || !reflect.DeepEqual(js.Uncovered, []int{5}) {
+		t.Fatalf("js = %+v", js)
+	}
+	if lib.Path != "" || lib.Percent != 0 || !reflect.DeepEqual(lib.Uncovered, []int{1}) {
+		t.Fatalf("lib = %+v", lib)
+	}
+}
@@ -2,10 +2,10 @@ package daemon
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net"
 	"net/http"
 	"os"
@@ -82,17 +82,22 @@ func Run(cfg Config) error {
 		return out
 	})
 
-	// Browser controller.
-	profileDir := filepath.Join(cfg.StateDir, "chrome-profile")
-	_ = os.RemoveAll(profileDir)
-	if err := os.MkdirAll(profileDir, 0o700); err != nil {
-		return err
+	// Browser controller. An attached browser brings its own profile.
+	profileDir := ""
+	if cfg.Attach == "" {
+		profileDir = filepath.Join(cfg.StateDir, "chrome-profile")
+		_ = os.RemoveAll(profileDir)
+		if err := os.MkdirAll(profileDir, 0o700); err != nil {
+			return err
+		}
 	}
 
 	rootCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	controller, err := browser.New(rootCtx, browser.Options{
+		Attach:       cfg.Attach,
+		AdoptTab:     cfg.AdoptTab,
 		BrowserBin:   cfg.BrowserBin,
 		Headless:     cfg.Headless,
 		UserDataDir:  profileDir,
@@ -109,10 +114,33 @@ func Run(cfg Config) error {
 	defer controller.Close()
 	controllerPtr.Store(controller)
 
+	logs := newLogBuffer(logBufferSize)
+	controller.Subscribe(logs.collect)
+	netlog := &browser.NetworkRecorder{PerNavigation: true, Max: netLogMaxPerTab}
+	controller.Subscribe(netlog.Handle)
+	harRec := &harRecorder{controller: controller}
+	interceptors := newRequestInterceptors(controller)
+	if cfg.RoutesFile != "" {
+		routes, err := LoadRoutes(cfg.RoutesFile)
+		if err != nil {
+			return fmt.Errorf("load routes: %w", err)
+		}
+		for _, r := range routes {
+			if _, err := interceptors.routes.add(r); err != nil {
+				return fmt.Errorf("load routes: %w", err)
+			}
+		}
+		if err := interceptors.install(rootCtx); err != nil {
+			return fmt.Errorf("load routes: %w", err)
+		}
+	}
+
 	if _, _, err := controller.Navigate(rootCtx, baseURL); err != nil {
 		return fmt.Errorf("navigate %s: %w", baseURL, err)
 	}
 
+	supervisor := newBrowserSupervisor(controller)
+
 	// RPC server.
 	rpch := rpc.NewHandler(token)
 	stopCh := make(chan struct{})
@@ -121,8 +149,11 @@ func Run(cfg Config) error {
 	rpch.Mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
 		loc, _ := controller.Location(r.Context())
 		title, _ := controller.Title(r.Context())
+		restarts, lastCrash, lastCrashAt := supervisor.Stats()
+		tabs, _ := controller.ListTabs(r.Context())
 		out := rpc.StatusResponse{
 			Running:       true,
+			Session:       cfg.Session,
 			BrowserAlive:  controller.Alive(r.Context()),
 			PID:           os.Getpid(),
 			Dir:           cfg.ServeDir,
@@ -131,10 +162,29 @@ func Run(cfg Config) error {
 			CurrentURL:    loc,
 			Title:         title,
 			Headless:      cfg.Headless,
+			Attached:      controller.Attached(),
 			BrowserPID:    controller.BrowserPID(),
 			DevToolsPort:  controller.DevToolsPort(),
 			DevToolsWSURL: controller.DevToolsWSURL(),
 			BrowserBinary: controller.BrowserBinary(),
+			Restarts:      restarts,
+			LastCrash:     lastCrash,
+			LastCrashAt:   lastCrashAt,
+			Tabs:          toRPCTabs(tabs),
+		}
+		if em := toRPCEmulation(controller.Emulation()); em != (rpc.Emulation{}) {
+			out.Emulation = &em
+		}
+		out.HARRecording = harRec.recording()
+		out.HARReplay = interceptors.replayEntries()
+		out.Routes = interceptors.routes.len()
+		out.Tracing, _ = controller.Tracing()
+		out.Coverage, _ = controller.Covering()
+		out.Screencast, _ = controller.Screencasting()
+		for _, t := range out.Tabs {
+			if t.Active {
+				out.ActiveTab = t.ID
+			}
 		}
 		rpcWriteJSON(w, http.StatusOK, out)
 	})
@@ -146,12 +196,12 @@ func Run(cfg Config) error {
 			return
 		}
 		u := normalizeURL(baseURL, req.URL)
-		loc, title, err := controller.Navigate(r.Context(), u)
+		rep, err := controller.Load(tabContext(r), u)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		rpcWriteJSON(w, http.StatusOK, rpc.GotoResponse{URL: loc, Title: title})
+		rpcWriteJSON(w, http.StatusOK, rpc.GotoResponse{URL: rep.URL, Title: rep.Title, LoadReport: toRPCLoadReport(rep)})
 	})
 
 	rpch.Mux.HandleFunc("/eval", func(w http.ResponseWriter, r *http.Request) {
@@ -160,7 +210,7 @@ func Run(cfg Config) error {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		val, err := controller.Eval(r.Context(), req.Expression)
+		val, err := controller.Eval(tabContext(r), req.Expression)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -169,11 +219,12 @@ func Run(cfg Config) error {
 	})
 
 	rpch.Mux.HandleFunc("/reload", func(w http.ResponseWriter, r *http.Request) {
-		if err := controller.Reload(r.Context()); err != nil {
+		rep, err := controller.ReloadReport(tabContext(r))
+		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		rpcWriteJSON(w, http.StatusOK, rpc.ReloadResponse{OK: true})
+		rpcWriteJSON(w, http.StatusOK, rpc.ReloadResponse{OK: true, URL: rep.URL, Title: rep.Title, LoadReport: toRPCLoadReport(rep)})
 	})
 
 	rpch.Mux.HandleFunc("/dom", func(w http.ResponseWriter, r *http.Request) {
@@ -190,9 +241,9 @@ func Run(cfg Config) error {
 		var err error
 		switch mode {
 		case "outer_html":
-			val, err = controller.OuterHTML(r.Context(), req.Selector)
+			val, err = controller.OuterHTML(tabContext(r), req.Selector)
 		case "text":
-			val, err = controller.Text(r.Context(), req.Selector)
+			val, err = controller.Text(tabContext(r), req.Selector)
 		default:
 			http.Error(w, "unknown mode", http.StatusBadRequest)
 			return
@@ -214,7 +265,7 @@ func Run(cfg Config) error {
 		if mode == "" {
 			mode = "outer_html"
 		}
-		vals, err := controller.QueryAll(r.Context(), req.Selector, mode)
+		vals, err := controller.QueryAll(tabContext(r), req.Selector, mode)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -228,7 +279,7 @@ func Run(cfg Config) error {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		val, err := controller.Attr(r.Context(), req.Selector, req.Name)
+		val, err := controller.Attr(tabContext(r), req.Selector, req.Name)
 		if err != nil {
 			if err.Error() == "element not found" {
 				http.Error(w, err.Error(), http.StatusNotFound)
@@ -246,7 +297,7 @@ func Run(cfg Config) error {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if err := controller.Click(r.Context(), req.Selector); err != nil {
+		if err := controller.Click(tabContext(r), req.Selector); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -259,7 +310,7 @@ func Run(cfg Config) error {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if err := controller.Type(r.Context(), req.Selector, req.Text, req.Clear); err != nil {
+		if err := controller.Type(tabContext(r), req.Selector, req.Text, req.Clear); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -280,30 +331,82 @@ func Run(cfg Config) error {
 		if req.TimeoutMS > 0 {
 			timeout = time.Duration(req.TimeoutMS) * time.Millisecond
 		}
-		if err := controller.Wait(r.Context(), req.Selector, state, timeout); err != nil {
+		if err := controller.Wait(tabContext(r), req.Selector, state, timeout); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 		rpcWriteJSON(w, http.StatusOK, rpc.DomWaitResponse{OK: true, State: state})
 	})
 
-	rpch.Mux.HandleFunc("/screenshot", func(w http.ResponseWriter, r *http.Request) {
-		var req rpc.ScreenshotRequest
+	rpch.Mux.HandleFunc("/tabs", func(w http.ResponseWriter, r *http.Request) {
+		tabs, err := controller.ListTabs(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.TabsResponse{Tabs: toRPCTabs(tabs)})
+	})
+
+	rpch.Mux.HandleFunc("/tabs/new", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.TabNewRequest
 		if err := rpcReadJSON(r, &req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		buf, err := controller.Screenshot(r.Context(), req.Selector)
+		t, err := controller.NewTab(r.Context(), normalizeURL(baseURL, req.URL))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		rpcWriteJSON(w, http.StatusOK, rpc.ScreenshotResponse{
-			Format: "png",
-			Base64: base64.StdEncoding.EncodeToString(buf),
-		})
+		rpcWriteJSON(w, http.StatusOK, rpc.TabResponse{Tab: toRPCTab(t)})
 	})
 
+	rpch.Mux.HandleFunc("/tabs/switch", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.TabRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		t, err := controller.SwitchTab(r.Context(), req.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.TabResponse{Tab: toRPCTab(t)})
+	})
+
+	rpch.Mux.HandleFunc("/tabs/close", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.TabRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := controller.CloseTab(r.Context(), req.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tabs, err := controller.ListTabs(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.TabsResponse{Tabs: toRPCTabs(tabs)})
+	})
+
+	registerEmulateHandlers(rpch.Mux, controller)
+	registerLogHandlers(rpch.Mux, logs)
+	registerNetworkLogHandlers(rpch.Mux, controller, netlog)
+	registerHARHandlers(rpch.Mux, harRec, interceptors)
+	registerRouteHandlers(rpch.Mux, interceptors)
+	registerPerfHandlers(rpch.Mux, controller)
+	registerTraceHandlers(rpch.Mux, controller)
+	registerProfileHandlers(rpch.Mux, controller)
+	registerCoverageHandlers(rpch.Mux, controller, staticHandler, baseURL)
+	registerScreenshotHandlers(rpch.Mux, controller)
+	registerPDFHandlers(rpch.Mux, controller)
+	registerRecordHandlers(rpch.Mux, controller)
+	registerA11yHandlers(rpch.Mux, controller)
+
 	rpch.Mux.HandleFunc("/stop", func(w http.ResponseWriter, r *http.Request) {
 		rpcWriteJSON(w, http.StatusOK, rpc.StopResponse{OK: true})
 		go func() {
@@ -334,6 +437,7 @@ func Run(cfg Config) error {
 		SocketPath:    socketPath,
 		Token:         token,
 		Headless:      cfg.Headless,
+		Attached:      controller.Attached(),
 		BrowserPID:    controller.BrowserPID(),
 		DevToolsPort:  controller.DevToolsPort(),
 		DevToolsWSURL: controller.DevToolsWSURL(),
@@ -350,6 +454,16 @@ func Run(cfg Config) error {
 		}
 	}()
 
+	// Relaunch the browser if it crashes and keep session.json pointing at it.
+	go supervisor.Run(rootCtx, func() {
+		sess.BrowserPID = controller.BrowserPID()
+		sess.DevToolsPort = controller.DevToolsPort()
+		sess.DevToolsWSURL = controller.DevToolsWSURL()
+		if err := state.Save(cfg.StateDir, sess); err != nil {
+			log.Printf("update session after relaunch: %v", err)
+		}
+	})
+
 	// File watcher for auto-reload.
 	if cfg.Watch {
 		go func() {
@@ -395,6 +509,23 @@ func normalizeURL(baseURL, in string) string {
 	return strings.TrimRight(baseURL, "/") + s
 }
 
+// tabContext scopes controller calls to the tab selected by the request, if any.
+func tabContext(r *http.Request) context.Context {
+	return browser.WithTab(r.Context(), r.Header.Get(rpc.TabHeader))
+}
+
+func toRPCTab(t browser.TabInfo) rpc.TabInfo {
+	return rpc.TabInfo{ID: t.ID, URL: t.URL, Title: t.Title, Active: t.Active}
+}
+
+func toRPCTabs(tabs []browser.TabInfo) []rpc.TabInfo {
+	out := make([]rpc.TabInfo, 0, len(tabs))
+	for _, t := range tabs {
+		out = append(out, toRPCTab(t))
+	}
+	return out
+}
+
 func rpcWriteJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,279 @@
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func registerEmulateHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/emulate/device", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.EmulateDeviceRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		v, err := resolveViewport(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := controller.SetViewport(r.Context(), &v); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+
+	mux.HandleFunc("/emulate/media", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.EmulateMediaRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m := mergeMedia(controller.Emulation().Media, req.Media)
+		if err := m.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := controller.SetMedia(r.Context(), &m); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+
+	mux.HandleFunc("/emulate/locale", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.EmulateLocaleRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := controller.SetLocale(r.Context(), req.Locale); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+
+	mux.HandleFunc("/emulate/timezone", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.EmulateTimezoneRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := controller.SetTimezone(r.Context(), req.Timezone); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+
+	mux.HandleFunc("/emulate/geolocation", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.EmulateGeolocationRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		g := browser.Geolocation{Latitude: req.Latitude, Longitude: req.Longitude, Accuracy: req.Accuracy}
+		if err := controller.SetGeolocation(r.Context(), &g); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+
+	mux.HandleFunc("/network/throttle", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.NetworkThrottleRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var n *browser.NetworkConditions
+		if !req.Off {
+			resolved, err := resolveThrottle(req.Network)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			n = &resolved
+		}
+		if err := controller.SetNetworkConditions(r.Context(), n); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+
+	mux.HandleFunc("/emulate/clear", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.EmulateClearRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx := r.Context()
+		clearers := []struct {
+			name string
+			fn   func() error
+		}{
+			{"device", func() error { return controller.SetViewport(ctx, nil) }},
+			{"media", func() error { return controller.SetMedia(ctx, nil) }},
+			{"locale", func() error { return controller.SetLocale(ctx, "") }},
+			{"timezone", func() error { return controller.SetTimezone(ctx, "") }},
+			{"geolocation", func() error { return controller.SetGeolocation(ctx, nil) }},
+			{"network", func() error { return controller.SetNetworkConditions(ctx, nil) }},
+		}
+		all := req.What == "" || req.What == "all"
+		matched := false
+		for _, cl := range clearers {
+			if !all && cl.name != req.What {
+				continue
+			}
+			matched = true
+			if err := cl.fn(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if !matched {
+			http.Error(w, fmt.Sprintf("unknown emulation %q (want device, media, locale, timezone, geolocation, network or all)", req.What), http.StatusBadRequest)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.EmulationResponse{Emulation: toRPCEmulation(controller.Emulation())})
+	})
+}
+
+// resolveViewport starts from the named preset (if any) and applies explicit
+// overrides on top.
+func resolveViewport(req rpc.EmulateDeviceRequest) (browser.Viewport, error) {
+	var v browser.Viewport
+	if req.Device != "" {
+		d, ok := browser.LookupDevice(req.Device)
+		if !ok {
+			return v, fmt.Errorf("unknown device %q (see `canvas emulate device --list`)", req.Device)
+		}
+		v = d
+	}
+	if req.Width > 0 {
+		v.Width = req.Width
+	}
+	if req.Height > 0 {
+		v.Height = req.Height
+	}
+	if req.DeviceScaleFactor > 0 {
+		v.DeviceScaleFactor = req.DeviceScaleFactor
+	}
+	if req.Mobile != nil {
+		v.Mobile = *req.Mobile
+	}
+	if req.Touch != nil {
+		v.Touch = *req.Touch
+	}
+	if req.UserAgent != "" {
+		v.UserAgent = req.UserAgent
+	}
+	if req.Landscape && v.Height > v.Width {
+		v.Width, v.Height = v.Height, v.Width
+	}
+	if v.Width <= 0 || v.Height <= 0 {
+		return v, fmt.Errorf("missing device or width/height")
+	}
+	if v.DeviceScaleFactor <= 0 {
+		v.DeviceScaleFactor = 1
+	}
+	return v, nil
+}
+
+// resolveThrottle starts from the named preset (if any) and applies explicit
+// values on top.
+func resolveThrottle(req rpc.Network) (browser.NetworkConditions, error) {
+	var n browser.NetworkConditions
+	if req.Preset != "" {
+		p, ok := browser.LookupNetworkPreset(req.Preset)
+		if !ok {
+			return n, fmt.Errorf("unknown network preset %q (want slow-3g, fast-3g or offline)", req.Preset)
+		}
+		n = p
+	}
+	if req.Offline {
+		n.Offline = true
+	}
+	if req.LatencyMs > 0 {
+		n.LatencyMs = req.LatencyMs
+	}
+	if req.DownloadKbps > 0 {
+		n.DownloadKbps = req.DownloadKbps
+	}
+	if req.UploadKbps > 0 {
+		n.UploadKbps = req.UploadKbps
+	}
+	if n == (browser.NetworkConditions{}) {
+		return n, fmt.Errorf("missing preset or latency/throughput values")
+	}
+	return n, nil
+}
+
+// mergeMedia applies the non-empty fields of req on top of cur. The value
+// "default" resets a single field to the browser default.
+func mergeMedia(cur *browser.Media, req rpc.Media) browser.Media {
+	var m browser.Media
+	if cur != nil {
+		m = *cur
+	}
+	set := func(dst *string, v string) {
+		switch v {
+		case "":
+		case "default":
+			*dst = ""
+		default:
+			*dst = v
+		}
+	}
+	set(&m.Type, req.Type)
+	set(&m.ColorScheme, req.ColorScheme)
+	set(&m.ReducedMotion, req.ReducedMotion)
+	set(&m.ForcedColors, req.ForcedColors)
+	set(&m.Contrast, req.Contrast)
+	return m
+}
+
+func toRPCEmulation(e browser.Emulation) rpc.Emulation {
+	var out rpc.Emulation
+	if v := e.Viewport; v != nil {
+		out.Viewport = &rpc.Viewport{
+			Device:            v.Device,
+			Width:             v.Width,
+			Height:            v.Height,
+			DeviceScaleFactor: v.DeviceScaleFactor,
+			Mobile:            v.Mobile,
+			Touch:             v.Touch,
+			UserAgent:         v.UserAgent,
+		}
+	}
+	if m := e.Media; m != nil {
+		out.Media = &rpc.Media{
+			Type:          m.Type,
+			ColorScheme:   m.ColorScheme,
+			ReducedMotion: m.ReducedMotion,
+			ForcedColors:  m.ForcedColors,
+			Contrast:      m.Contrast,
+		}
+	}
+	out.Locale = e.Locale
+	out.Timezone = e.Timezone
+	if g := e.Geolocation; g != nil {
+		out.Geolocation = &rpc.Geolocation{Latitude: g.Latitude, Longitude: g.Longitude, Accuracy: g.Accuracy}
+	}
+	if n := e.Network; n != nil {
+		out.Network = &rpc.Network{
+			Preset:       n.Preset,
+			Offline:      n.Offline,
+			LatencyMs:    n.LatencyMs,
+			DownloadKbps: n.DownloadKbps,
+			UploadKbps:   n.UploadKbps,
+		}
+	}
+	return out
+}
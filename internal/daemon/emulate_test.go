@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestResolveViewport(t *testing.T) {
+	v, err := resolveViewport(rpc.EmulateDeviceRequest{Device: "iphone-15"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Device != "iPhone 15" || v.Width != 393 || v.Height != 852 || v.DeviceScaleFactor != 3 || !v.Mobile || !v.Touch || v.UserAgent == "" {
+		t.Fatalf("preset = %#v", v)
+	}
+
+	no := false
+	v, err = resolveViewport(rpc.EmulateDeviceRequest{Device: "iPad Air", Landscape: true, Touch: &no, DeviceScaleFactor: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Width != 1180 || v.Height != 820 || v.Touch || v.DeviceScaleFactor != 1 {
+		t.Fatalf("override = %#v", v)
+	}
+
+	v, err = resolveViewport(rpc.EmulateDeviceRequest{Width: 800, Height: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Device != "" || v.DeviceScaleFactor != 1 || v.Mobile {
+		t.Fatalf("explicit = %#v", v)
+	}
+
+	if _, err := resolveViewport(rpc.EmulateDeviceRequest{Device: "Nokia 3310"}); err == nil {
+		t.Fatal("expected unknown device error")
+	}
+	if _, err := resolveViewport(rpc.EmulateDeviceRequest{Width: 800}); err == nil {
+		t.Fatal("expected missing height error")
+	}
+}
+
+func TestMergeMedia(t *testing.T) {
+	cur := &browser.Media{ColorScheme: "dark", Type: "print"}
+	m := mergeMedia(cur, rpc.Media{ReducedMotion: "reduce", Type: "default"})
+	want := browser.Media{ColorScheme: "dark", ReducedMotion: "reduce"}
+	if m != want {
+		t.Fatalf("merge = %#v, want %#v", m, want)
+	}
+	if cur.Type != "print" {
+		t.Fatal("mergeMedia modified the current override")
+	}
+
+	if err := mergeMedia(nil, rpc.Media{ColorScheme: "purple"}).Validate(); err == nil {
+		t.Fatal("expected invalid color scheme error")
+	}
+	if !mergeMedia(cur, rpc.Media{Type: "default", ColorScheme: "default"}).IsZero() {
+		t.Fatal("expected all fields reset")
+	}
+}
+
+func TestResolveThrottle(t *testing.T) {
+	n, err := resolveThrottle(rpc.Network{Preset: "Slow-3G", LatencyMs: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Preset != "slow-3g" || n.LatencyMs != 100 || n.DownloadKbps != 400 || n.Offline {
+		t.Fatalf("preset override = %#v", n)
+	}
+
+	n, err = resolveThrottle(rpc.Network{Preset: "offline"})
+	if err != nil || !n.Offline {
+		t.Fatalf("offline = %#v, %v", n, err)
+	}
+
+	n, err = resolveThrottle(rpc.Network{DownloadKbps: 1000})
+	if err != nil || n.Preset != "" || n.DownloadKbps != 1000 || n.UploadKbps != 0 {
+		t.Fatalf("custom = %#v, %v", n, err)
+	}
+
+	if _, err := resolveThrottle(rpc.Network{Preset: "5g"}); err == nil {
+		t.Fatal("expected unknown preset error")
+	}
+	if _, err := resolveThrottle(rpc.Network{}); err == nil {
+		t.Fatal("expected missing values error")
+	}
+}
@@ -0,0 +1,304 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/har"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+// harBodyWait bounds how long `har stop` waits for response bodies that are
+// still being fetched.
+const harBodyWait = 5 * time.Second
+
+// harRecorder records all tabs' traffic, including response bodies, between
+// `har start` and `har stop`.
+type harRecorder struct {
+	controller *browser.Controller
+
+	mu          sync.Mutex
+	rec         *browser.NetworkRecorder
+	unsubscribe func()
+	bodies      map[string][]byte // tab + "/" + request ID
+	// pending counts rec's body fetches. Each recording has its own, since
+	// fetches abandoned by stop may still be running when the next starts.
+	pending *sync.WaitGroup
+}
+
+func (h *harRecorder) start() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rec != nil {
+		return errors.New("already recording (run `canvas har stop` first)")
+	}
+	h.rec = &browser.NetworkRecorder{}
+	h.bodies = map[string][]byte{}
+	h.pending = &sync.WaitGroup{}
+	rec, bodies, pending := h.rec, h.bodies, h.pending
+	h.unsubscribe = h.controller.Subscribe(func(tabID string, ev any) {
+		rec.Handle(tabID, ev)
+		if e, ok := ev.(*network.EventLoadingFinished); ok {
+			h.fetchBody(bodies, pending, tabID, string(e.RequestID))
+		}
+	})
+	return nil
+}
+
+func (h *harRecorder) recording() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.rec != nil
+}
+
+// fetchBody grabs a response body into bodies while Chrome still has it. It
+// runs off the event dispatcher, which must never block.
+func (h *harRecorder) fetchBody(bodies map[string][]byte, pending *sync.WaitGroup, tabID, requestID string) {
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+		ctx, cancel := context.WithTimeout(browser.WithTab(context.Background(), tabID), harBodyWait)
+		defer cancel()
+		body, err := h.controller.ResponseBody(ctx, requestID)
+		if err != nil {
+			return
+		}
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		bodies[tabID+"/"+requestID] = body
+	}()
+}
+
+// stop ends the recording and returns the archive.
+func (h *harRecorder) stop() (*har.HAR, error) {
+	h.mu.Lock()
+	rec, bodies, pending := h.rec, h.bodies, h.pending
+	if rec == nil {
+		h.mu.Unlock()
+		return nil, errors.New("not recording (run `canvas har start` first)")
+	}
+	h.unsubscribe()
+	// A concurrent stop now finds nothing to stop. Bodies still being
+	// fetched land in bodies.
+	h.rec, h.bodies, h.unsubscribe, h.pending = nil, nil, nil, nil
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(harBodyWait):
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return buildHAR(rec.Requests(""), bodies), nil
+}
+
+// buildHAR converts recorded requests into a HAR archive. bodies is keyed by
+// tab + "/" + request ID.
+func buildHAR(reqs []browser.NetworkRequest, bodies map[string][]byte) *har.HAR {
+	out := har.New("canvas", canvasVersion())
+	for _, nr := range reqs {
+		if strings.HasPrefix(nr.URL, "data:") {
+			continue
+		}
+		e := har.Entry{
+			StartedDateTime: nr.Started,
+			Time:            ms(nr.Duration),
+			ServerIPAddress: nr.RemoteIP,
+			Request: har.Request{
+				Method:      nr.Method,
+				URL:         nr.URL,
+				HTTPVersion: httpVersion(nr.Protocol),
+				Cookies:     []har.Cookie{},
+				Headers:     har.Headers(nr.RequestHeaders),
+				QueryString: har.QueryString(nr.URL),
+				HeadersSize: -1,
+				BodySize:    int64(len(nr.PostData)),
+			},
+			Response: har.Response{
+				Status:      nr.Status,
+				StatusText:  nr.StatusText,
+				HTTPVersion: httpVersion(nr.Protocol),
+				Cookies:     []har.Cookie{},
+				Headers:     har.Headers(nr.ResponseHeaders),
+				Content:     har.Content{MimeType: nr.MIMEType},
+				RedirectURL: nr.RedirectURL,
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings: harTimings(nr),
+		}
+		if len(nr.PostData) > 0 {
+			e.Request.PostData = &har.PostData{
+				MimeType: headerValue(nr.RequestHeaders, "Content-Type"),
+				Text:     string(nr.PostData),
+			}
+		}
+		if nr.Finished {
+			e.Response.BodySize = nr.Size
+		}
+		if body, ok := bodies[nr.Tab+"/"+nr.ID]; ok && nr.RedirectURL == "" {
+			e.Response.Content.SetBody(body)
+		}
+		out.Log.Entries = append(out.Log.Entries, e)
+	}
+	return out
+}
+
+func harTimings(nr browser.NetworkRequest) har.Timings {
+	total := ms(nr.Duration)
+	t := nr.Timing
+	if t == nil {
+		return har.Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: total}
+	}
+	out := har.Timings{Blocked: t.Blocked, DNS: t.DNS, Connect: t.Connect, SSL: t.SSL, Send: t.Send, Wait: t.Wait}
+	used := t.Blocked + t.Send + t.Wait + max(t.DNS, 0) + max(t.Connect, 0)
+	out.Receive = max(total-used, 0)
+	return out
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func httpVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "":
+		return ""
+	case "h2":
+		return "HTTP/2"
+	case "h3":
+		return "HTTP/3"
+	default:
+		return strings.ToUpper(protocol)
+	}
+}
+
+func headerValue(h map[string]string, name string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+func canvasVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// harReplay answers requests from a recorded archive.
+type harReplay struct {
+	archive *har.HAR
+	// abort fails requests missing from the archive instead of letting them
+	// through to the network.
+	abort bool
+}
+
+// hopHeaders are recorded but describe the original transfer, not the
+// decoded body we fulfill with.
+var hopHeaders = map[string]bool{
+	"content-encoding":  true,
+	"content-length":    true,
+	"transfer-encoding": true,
+	"connection":        true,
+	"keep-alive":        true,
+}
+
+func (p *harReplay) intercept(req browser.InterceptedRequest) (browser.InterceptResult, bool) {
+	e, ok := p.archive.Find(req.Method, req.URL, req.PostData)
+	if !ok {
+		if p.abort {
+			return browser.InterceptResult{Fail: string(network.ErrorReasonFailed)}, true
+		}
+		return browser.InterceptResult{}, false
+	}
+	if e.Response.Status == 0 {
+		return browser.InterceptResult{Fail: string(network.ErrorReasonFailed)}, true
+	}
+	body, err := e.Response.Content.Body()
+	if err != nil {
+		return browser.InterceptResult{Fail: string(network.ErrorReasonFailed)}, true
+	}
+	headers := map[string]string{}
+	for k, v := range har.HeaderMap(e.Response.Headers) {
+		if hopHeaders[strings.ToLower(k)] || strings.HasPrefix(k, ":") {
+			continue
+		}
+		headers[k] = v
+	}
+	return browser.InterceptResult{Status: e.Response.Status, Headers: headers, Body: body}, true
+}
+
+func registerHARHandlers(mux *http.ServeMux, recorder *harRecorder, interceptors *requestInterceptors) {
+	mux.HandleFunc("/har/start", func(w http.ResponseWriter, r *http.Request) {
+		if err := recorder.start(); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.HARStartResponse{OK: true})
+	})
+
+	mux.HandleFunc("/har/stop", func(w http.ResponseWriter, r *http.Request) {
+		archive, err := recorder.stop()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		data, err := json.MarshalIndent(archive, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.HARStopResponse{Entries: len(archive.Log.Entries), HAR: data})
+	})
+
+	mux.HandleFunc("/har/replay", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.HARReplayRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Off {
+			if err := interceptors.setReplay(r.Context(), nil); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			rpcWriteJSON(w, http.StatusOK, rpc.HARReplayResponse{})
+			return
+		}
+		switch req.NotFound {
+		case "", "fallback", "abort":
+		default:
+			http.Error(w, `not_found must be "fallback" or "abort"`, http.StatusBadRequest)
+			return
+		}
+		archive, err := har.Parse(req.HAR)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := interceptors.setReplay(r.Context(), &harReplay{archive: archive, abort: req.NotFound == "abort"}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.HARReplayResponse{Active: true, Entries: len(archive.Log.Entries)})
+	})
+}
@@ -0,0 +1,120 @@
+package daemon
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/har"
+)
+
+func TestBuildHAR(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	reqs := []browser.NetworkRequest{
+		{
+			ID: "1", Tab: "T", URL: "http://x/old", Method: "GET", Status: 301, Finished: true,
+			RedirectURL: "http://x/new", Started: start,
+		},
+		{
+			ID: "1", Tab: "T", URL: "http://x/new?a=1", Method: "GET", Status: 200, StatusText: "OK",
+			MIMEType: "text/html", Protocol: "h2", Size: 120, Finished: true, Started: start,
+			Duration:        30 * time.Millisecond,
+			ResponseHeaders: map[string]string{"Content-Type": "text/html"},
+			Timing:          &browser.Timing{Blocked: 1, DNS: -1, Connect: -1, SSL: -1, Send: 1, Wait: 20},
+		},
+		{
+			ID: "2", Tab: "T", URL: "http://x/api", Method: "POST", Failure: "net::ERR_FAILED", Started: start,
+			RequestHeaders: map[string]string{"content-type": "application/json"},
+			PostData:       []byte(`{"q":1}`),
+		},
+		{ID: "3", Tab: "T", URL: "data:image/png;base64,AAAA", Method: "GET", Status: 200},
+	}
+	h := buildHAR(reqs, map[string][]byte{"T/1": []byte("<p>hi</p>")})
+
+	if len(h.Log.Entries) != 3 {
+		t.Fatalf("entries = %d", len(h.Log.Entries))
+	}
+	hop, page, post := h.Log.Entries[0], h.Log.Entries[1], h.Log.Entries[2]
+	if hop.Response.RedirectURL != "http://x/new" || hop.Response.Content.Text != "" {
+		t.Fatalf("redirect hop = %+v", hop.Response)
+	}
+	if page.Response.Content.Text != "<p>hi</p>" || page.Response.HTTPVersion != "HTTP/2" || page.Time != 30 {
+		t.Fatalf("page = %+v", page)
+	}
+	if page.Timings.Receive != 8 || page.Timings.DNS != -1 {
+		t.Fatalf("timings = %+v", page.Timings)
+	}
+	if len(page.Request.QueryString) != 1 || page.Request.QueryString[0].Value != "1" {
+		t.Fatalf("query = %+v", page.Request.QueryString)
+	}
+	if post.Request.PostData == nil || post.Request.PostData.MimeType != "application/json" || post.Response.Status != 0 {
+		t.Fatalf("post = %+v", post)
+	}
+}
+
+func TestHARReplayIntercept(t *testing.T) {
+	archive := har.New("canvas", "test")
+	e := har.Entry{
+		Request: har.Request{Method: "GET", URL: "http://x/app.js"},
+		Response: har.Response{Status: 200, Headers: []har.NameValue{
+			{Name: "Content-Type", Value: "text/javascript"},
+			{Name: "Content-Encoding", Value: "gzip"},
+			{Name: "Content-Length", Value: "99"},
+		}},
+	}
+	e.Response.Content.MimeType = "text/javascript"
+	e.Response.Content.SetBody([]byte("console.log(1)"))
+	archive.Log.Entries = append(archive.Log.Entries, e)
+
+	p := &harReplay{archive: archive}
+	res, ok := p.intercept(browser.InterceptedRequest{Method: "GET", URL: "http://x/app.js"})
+	if !ok || res.Status != 200 || string(res.Body) != "console.log(1)" {
+		t.Fatalf("hit = %+v %v", res, ok)
+	}
+	if len(res.Headers) != 1 || res.Headers["Content-Type"] != "text/javascript" {
+		t.Fatalf("headers = %+v", res.Headers)
+	}
+	if _, ok := p.intercept(browser.InterceptedRequest{Method: "GET", URL: "http://x/other.js"}); ok {
+		t.Fatal("fallback mode should let unmatched requests through")
+	}
+	p.abort = true
+	if res, ok := p.intercept(browser.InterceptedRequest{Method: "GET", URL: "http://x/other.js"}); !ok || res.Fail != "Failed" {
+		t.Fatalf("abort = %+v %v", res, ok)
+	}
+}
+
+func TestHARRecorder_ConcurrentStops(t *testing.T) {
+	pending := &sync.WaitGroup{}
+	pending.Add(1) // a body fetch keeps both stops waiting
+	h := &harRecorder{
+		rec:         &browser.NetworkRecorder{},
+		bodies:      map[string][]byte{},
+		pending:     pending,
+		unsubscribe: func() {},
+	}
+
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := h.stop()
+			errs <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	pending.Done()
+
+	var failed int
+	for range 2 {
+		if err := <-errs; err != nil {
+			if !strings.Contains(err.Error(), "not recording") {
+				t.Fatalf("err = %v", err)
+			}
+			failed++
+		}
+	}
+	if failed != 1 || h.recording() {
+		t.Fatalf("failed stops = %d, still recording = %v", failed, h.recording())
+	}
+}
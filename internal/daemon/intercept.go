@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+
+	"github.com/steipete/canvas/internal/browser"
+)
+
+// requestInterceptors combines the daemon's request handlers into the
+// controller's single interceptor: mock routes first, then HAR replay.
+type requestInterceptors struct {
+	controller *browser.Controller
+	routes     *routeTable
+
+	mu     sync.Mutex
+	replay *harReplay
+}
+
+func newRequestInterceptors(controller *browser.Controller) *requestInterceptors {
+	return &requestInterceptors{controller: controller, routes: &routeTable{}}
+}
+
+func (ri *requestInterceptors) setReplay(ctx context.Context, p *harReplay) error {
+	ri.mu.Lock()
+	ri.replay = p
+	ri.mu.Unlock()
+	return ri.install(ctx)
+}
+
+// install turns interception on while any handler is active.
+func (ri *requestInterceptors) install(ctx context.Context) error {
+	ri.mu.Lock()
+	active := ri.replay != nil || ri.routes.len() > 0
+	ri.mu.Unlock()
+	if !active {
+		return ri.controller.SetInterceptor(ctx, nil)
+	}
+	return ri.controller.SetInterceptor(ctx, ri.intercept)
+}
+
+func (ri *requestInterceptors) intercept(req browser.InterceptedRequest) (browser.InterceptResult, bool) {
+	if res, ok := ri.routes.match(req); ok {
+		return res, true
+	}
+	ri.mu.Lock()
+	replay := ri.replay
+	ri.mu.Unlock()
+	if replay != nil {
+		return replay.intercept(req)
+	}
+	return browser.InterceptResult{}, false
+}
+
+func (ri *requestInterceptors) replayEntries() int {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	if ri.replay == nil {
+		return 0
+	}
+	return len(ri.replay.archive.Log.Entries)
+}
@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+const logBufferSize = 1000
+
+// logBuffer keeps the most recent page log entries (console, exceptions,
+// browser log) in memory.
+type logBuffer struct {
+	mu  sync.Mutex
+	max int
+	// entries is a ring once it holds max entries; head is the oldest.
+	entries []rpc.LogEntry
+	head    int
+	seq     int64
+}
+
+func newLogBuffer(max int) *logBuffer {
+	return &logBuffer{max: max}
+}
+
+func (b *logBuffer) add(e rpc.LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	e.Seq = b.seq
+	if len(b.entries) < b.max {
+		b.entries = append(b.entries, e)
+		return
+	}
+	b.entries[b.head] = e
+	b.head = (b.head + 1) % b.max
+}
+
+func (b *logBuffer) query(req rpc.LogsRequest) rpc.LogsResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	minRank := 0
+	if req.Level != "" {
+		minRank = browser.LevelRank(req.Level)
+	}
+	out := rpc.LogsResponse{Entries: []rpc.LogEntry{}, Last: b.seq}
+	for i := range b.entries {
+		e := b.entries[(b.head+i)%len(b.entries)]
+		if e.Seq <= req.After || e.Time.Before(req.Since) || browser.LevelRank(e.Level) < minRank {
+			continue
+		}
+		out.Entries = append(out.Entries, e)
+	}
+	if req.Limit > 0 && len(out.Entries) > req.Limit {
+		out.Entries = out.Entries[len(out.Entries)-req.Limit:]
+	}
+	return out
+}
+
+// collect is a browser.Controller subscriber.
+func (b *logBuffer) collect(tabID string, ev any) {
+	e, ok := browser.LogEntryFromEvent(ev)
+	if !ok {
+		return
+	}
+	b.add(toRPCLogEntry(tabID, e))
+}
+
+func toRPCLogEntry(tabID string, e browser.LogEntry) rpc.LogEntry {
+	out := rpc.LogEntry{
+		Time:   e.Time,
+		Level:  e.Level,
+		Source: e.Source,
+		Type:   e.Type,
+		Text:   e.Text,
+		URL:    e.URL,
+		Line:   e.Line,
+		Column: e.Column,
+		Tab:    tabID,
+	}
+	for _, f := range e.Stack {
+		out.Stack = append(out.Stack, rpc.StackFrame{Function: f.Function, URL: f.URL, Line: f.Line, Column: f.Column})
+	}
+	return out
+}
+
+func toRPCLoadReport(r browser.LoadReport) rpc.LoadReport {
+	out := rpc.LoadReport{Status: r.Status}
+	for _, f := range r.FailedRequests {
+		out.FailedRequests = append(out.FailedRequests, rpc.FailedRequest{URL: f.URL, Type: f.Type, Status: f.Status, Error: f.Error})
+	}
+	for _, e := range r.Errors {
+		out.Errors = append(out.Errors, toRPCLogEntry("", e))
+	}
+	return out
+}
+
+func registerLogHandlers(mux *http.ServeMux, logs *logBuffer) {
+	mux.HandleFunc("/logs", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.LogsRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, logs.query(req))
+	})
+}
@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestLogBuffer_EvictsAndFilters(t *testing.T) {
+	b := newLogBuffer(3)
+	base := time.Unix(1700000000, 0)
+	for i, level := range []string{"info", "error", "debug", "warning", "error"} {
+		b.add(rpc.LogEntry{Time: base.Add(time.Duration(i) * time.Second), Level: level, Text: fmt.Sprint(i)})
+	}
+
+	all := b.query(rpc.LogsRequest{})
+	if all.Last != 5 || len(all.Entries) != 3 || all.Entries[0].Seq != 3 || all.Entries[2].Text != "4" {
+		t.Fatalf("all = %+v", all)
+	}
+
+	if got := b.query(rpc.LogsRequest{Level: "warning"}); len(got.Entries) != 2 || got.Entries[0].Level != "warning" {
+		t.Fatalf("level = %+v", got.Entries)
+	}
+	if got := b.query(rpc.LogsRequest{After: 4}); len(got.Entries) != 1 || got.Entries[0].Seq != 5 {
+		t.Fatalf("after = %+v", got.Entries)
+	}
+	if got := b.query(rpc.LogsRequest{Since: base.Add(3 * time.Second)}); len(got.Entries) != 2 {
+		t.Fatalf("since = %+v", got.Entries)
+	}
+	if got := b.query(rpc.LogsRequest{Limit: 1}); len(got.Entries) != 1 || got.Entries[0].Seq != 5 {
+		t.Fatalf("limit = %+v", got.Entries)
+	}
+
+	for i := range 7 {
+		b.add(rpc.LogEntry{Level: "info", Text: fmt.Sprint(5 + i)})
+	}
+	if got := b.query(rpc.LogsRequest{}); len(got.Entries) != 3 || got.Entries[0].Text != "9" || got.Entries[1].Text != "10" || got.Entries[2].Text != "11" {
+		t.Fatalf("after wrapping = %+v", got.Entries)
+	}
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+const netLogMaxPerTab = 2000
+
+func registerNetworkLogHandlers(mux *http.ServeMux, controller *browser.Controller, netlog *browser.NetworkRecorder) {
+	mux.HandleFunc("/network/log", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.NetworkLogRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tabID, err := controller.TabID(tabContext(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.NetworkLogResponse{Requests: filterNetworkRequests(netlog.Requests(tabID), req)})
+	})
+
+	mux.HandleFunc("/network/body", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.NetworkBodyRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		nr, ok := netlog.Lookup(req.ID)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown request %q (see `canvas network log`)", req.ID), http.StatusNotFound)
+			return
+		}
+		body, err := controller.ResponseBody(browser.WithTab(r.Context(), nr.Tab), nr.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.NetworkBodyResponse{ID: nr.ID, URL: nr.URL, MIMEType: nr.MIMEType, Body: body})
+	})
+}
+
+func filterNetworkRequests(reqs []browser.NetworkRequest, q rpc.NetworkLogRequest) []rpc.NetworkRequest {
+	filter := strings.ToLower(q.Filter)
+	out := []rpc.NetworkRequest{}
+	for _, nr := range reqs {
+		if q.Failed && !nr.Failed() {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(nr.URL), filter) {
+			continue
+		}
+		out = append(out, rpc.NetworkRequest{
+			ID:         nr.ID,
+			Tab:        nr.Tab,
+			URL:        nr.URL,
+			Method:     nr.Method,
+			Type:       nr.Type,
+			Status:     nr.Status,
+			MIMEType:   nr.MIMEType,
+			Size:       nr.Size,
+			Started:    nr.Started,
+			DurationMs: float64(nr.Duration.Microseconds()) / 1000,
+			Initiator:  nr.Initiator,
+			Failure:    nr.Failure,
+			FromCache:  nr.FromCache,
+			Finished:   nr.Finished,
+		})
+	}
+	return out
+}
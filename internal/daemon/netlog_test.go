@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestFilterNetworkRequests(t *testing.T) {
+	reqs := []browser.NetworkRequest{
+		{ID: "1", URL: "http://127.0.0.1:1/", Status: 200, Duration: 1500 * time.Microsecond},
+		{ID: "2", URL: "http://127.0.0.1:1/API/items", Status: 404},
+		{ID: "3", URL: "https://fonts.example/f.woff2", Failure: "net::ERR_NAME_NOT_RESOLVED"},
+	}
+
+	all := filterNetworkRequests(reqs, rpc.NetworkLogRequest{})
+	if len(all) != 3 || all[0].DurationMs != 1.5 {
+		t.Fatalf("all = %+v", all)
+	}
+	if got := filterNetworkRequests(reqs, rpc.NetworkLogRequest{Failed: true}); len(got) != 2 || got[0].ID != "2" {
+		t.Fatalf("failed = %+v", got)
+	}
+	if got := filterNetworkRequests(reqs, rpc.NetworkLogRequest{Filter: "api"}); len(got) != 1 || got[0].ID != "2" {
+		t.Fatalf("filter = %+v", got)
+	}
+	if got := filterNetworkRequests(nil, rpc.NetworkLogRequest{}); got == nil {
+		t.Fatal("expected an empty, non-nil slice")
+	}
+}
@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func registerPDFHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/pdf", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.PDFRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		buf, err := controller.PDF(tabContext(r), pdfOptions(req))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.PDFResponse{Base64: base64.StdEncoding.EncodeToString(buf)})
+	})
+}
+
+func pdfOptions(req rpc.PDFRequest) browser.PDFOptions {
+	opts := browser.PDFOptions{
+		PaperWidth:        req.PaperWidth,
+		PaperHeight:       req.PaperHeight,
+		Landscape:         req.Landscape,
+		Scale:             req.Scale,
+		Background:        req.Background,
+		HeaderTemplate:    req.HeaderTemplate,
+		FooterTemplate:    req.FooterTemplate,
+		PageRanges:        req.PageRanges,
+		PreferCSSPageSize: req.PreferCSSPageSize,
+	}
+	if m := req.Margins; m != nil {
+		opts.Margins = &browser.PDFMargins{Top: m.Top, Right: m.Right, Bottom: m.Bottom, Left: m.Left}
+	}
+	return opts
+}
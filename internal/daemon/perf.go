@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+const (
+	perfMaxRuns = 50
+	// perfSettle lets late paints and layout shifts land after a reload.
+	perfSettle = 500 * time.Millisecond
+)
+
+// perfMetrics picks the Performance.getMetrics counters worth a row.
+// Durations come in seconds and are reported in ms.
+var perfMetrics = []struct {
+	metric, name, unit string
+	scale              float64
+}{
+	{"JSHeapUsedSize", "js_heap_used", "bytes", 1},
+	{"JSHeapTotalSize", "js_heap_total", "bytes", 1},
+	{"Nodes", "nodes", "count", 1},
+	{"Documents", "documents", "count", 1},
+	{"Frames", "frames", "count", 1},
+	{"JSEventListeners", "event_listeners", "count", 1},
+	{"LayoutCount", "layout_count", "count", 1},
+	{"RecalcStyleCount", "recalc_style_count", "count", 1},
+	{"LayoutDuration", "layout_duration", "ms", 1000},
+	{"RecalcStyleDuration", "recalc_style_duration", "ms", 1000},
+	{"ScriptDuration", "script_duration", "ms", 1000},
+	{"TaskDuration", "task_duration", "ms", 1000},
+}
+
+// perfValues flattens a report into rows; values that weren't observed are
+// left out.
+func perfValues(r browser.PerfReport) []rpc.PerfValue {
+	var out []rpc.PerfValue
+	add := func(name, unit string, v float64) {
+		if v >= 0 {
+			out = append(out, rpc.PerfValue{Name: name, Unit: unit, Value: v})
+		}
+	}
+	add("ttfb", "ms", r.TTFB)
+	add("fcp", "ms", r.FCP)
+	add("lcp", "ms", r.LCP)
+	add("cls", "score", r.CLS)
+	add("inp", "ms", r.INP)
+	add("fid", "ms", r.FID)
+	add("dom_content_loaded", "ms", r.DOMContentLoaded)
+	add("load", "ms", r.Load)
+	add("transfer_size", "bytes", r.TransferSize)
+	for _, m := range perfMetrics {
+		if v, ok := r.Metrics[m.metric]; ok {
+			add(m.name, m.unit, v*m.scale)
+		}
+	}
+	return out
+}
+
+// summarizePerf merges the rows of several runs into median (Value) and p95
+// per metric, in first-seen order.
+func summarizePerf(runs [][]rpc.PerfValue) []rpc.PerfValue {
+	var order []string
+	byName := map[string]*rpc.PerfValue{}
+	for _, run := range runs {
+		for _, v := range run {
+			agg := byName[v.Name]
+			if agg == nil {
+				agg = &rpc.PerfValue{Name: v.Name, Unit: v.Unit}
+				byName[v.Name] = agg
+				order = append(order, v.Name)
+			}
+			agg.Samples = append(agg.Samples, v.Value)
+		}
+	}
+	out := make([]rpc.PerfValue, 0, len(order))
+	for _, name := range order {
+		agg := byName[name]
+		sorted := append([]float64(nil), agg.Samples...)
+		sort.Float64s(sorted)
+		agg.Value = percentile(sorted, 50)
+		agg.P95 = percentile(sorted, 95)
+		out = append(out, *agg)
+	}
+	return out
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func registerPerfHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/perf", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.PerfRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		runs := max(req.Runs, 1)
+		if runs > perfMaxRuns {
+			http.Error(w, fmt.Sprintf("at most %d runs", perfMaxRuns), http.StatusBadRequest)
+			return
+		}
+		if runs > 1 && !req.Reload {
+			http.Error(w, "multiple runs need reload", http.StatusBadRequest)
+			return
+		}
+
+		ctx := tabContext(r)
+		var (
+			samples [][]rpc.PerfValue
+			last    browser.PerfReport
+		)
+		for i := 0; i < runs; i++ {
+			if req.Reload {
+				if _, err := controller.ReloadReport(ctx); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err := sleepCtx(ctx, perfSettle); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+			rep, err := controller.Perf(ctx)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			samples = append(samples, perfValues(rep))
+			last = rep
+		}
+
+		out := rpc.PerfResponse{URL: last.URL, Runs: runs, Metrics: last.Metrics}
+		if runs == 1 {
+			out.Values = samples[0]
+		} else {
+			out.Values = summarizePerf(samples)
+		}
+		if out.Values == nil {
+			out.Values = []rpc.PerfValue{}
+		}
+		rpcWriteJSON(w, http.StatusOK, out)
+	})
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestPerfValues(t *testing.T) {
+	rep := browser.PerfReport{
+		TTFB: 12, FCP: 80, LCP: 120, CLS: 0, INP: -1, FID: -1, DOMContentLoaded: 90, Load: 150, TransferSize: 2048,
+		Metrics: map[string]float64{"JSHeapUsedSize": 1e6, "Nodes": 42, "ScriptDuration": 0.25, "Unlisted": 7},
+	}
+	got := map[string]rpc.PerfValue{}
+	for _, v := range perfValues(rep) {
+		got[v.Name] = v
+	}
+	if _, ok := got["inp"]; ok {
+		t.Fatal("unobserved INP should be left out")
+	}
+	if v := got["cls"]; v.Unit != "score" || v.Value != 0 {
+		t.Fatalf("cls = %+v", v)
+	}
+	if v := got["script_duration"]; v.Unit != "ms" || v.Value != 250 {
+		t.Fatalf("script_duration = %+v", v)
+	}
+	if v := got["nodes"]; v.Unit != "count" || v.Value != 42 {
+		t.Fatalf("nodes = %+v", v)
+	}
+	if _, ok := got["Unlisted"]; ok || len(got) != 10 {
+		t.Fatalf("values = %+v", got)
+	}
+}
+
+func TestSummarizePerf(t *testing.T) {
+	var runs [][]rpc.PerfValue
+	for _, lcp := range []float64{300, 100, 200, 500, 400} {
+		run := []rpc.PerfValue{{Name: "lcp", Unit: "ms", Value: lcp}}
+		if lcp != 500 {
+			run = append(run, rpc.PerfValue{Name: "inp", Unit: "ms", Value: lcp / 10})
+		}
+		runs = append(runs, run)
+	}
+	got := summarizePerf(runs)
+	if len(got) != 2 || got[0].Name != "lcp" || got[1].Name != "inp" {
+		t.Fatalf("summary = %+v", got)
+	}
+	if got[0].Value != 300 || got[0].P95 != 500 || !reflect.DeepEqual(got[0].Samples, []float64{300, 100, 200, 500, 400}) {
+		t.Fatalf("lcp = %+v", got[0])
+	}
+	if got[1].Value != 20 || got[1].P95 != 40 || len(got[1].Samples) != 4 {
+		t.Fatalf("inp = %+v", got[1])
+	}
+}
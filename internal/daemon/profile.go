@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+const (
+	profileMaxDuration = 5 * time.Minute
+	profileDefaultTop  = 15
+)
+
+func registerProfileHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/profile/cpu", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.ProfileCPURequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		d := time.Duration(req.DurationMs) * time.Millisecond
+		if d <= 0 || d > profileMaxDuration {
+			http.Error(w, fmt.Sprintf("duration must be between 1ms and %s", profileMaxDuration), http.StatusBadRequest)
+			return
+		}
+		profile, err := controller.CPUProfile(tabContext(r), d, time.Duration(req.IntervalUs)*time.Microsecond)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := json.Marshal(profile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		top := req.Top
+		if top <= 0 {
+			top = profileDefaultTop
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.ProfileCPUResponse{
+			Profile: data,
+			Summary: toRPCProfileSummary(browser.SummarizeCPUProfile(profile, top)),
+		})
+	})
+
+	// /profile/heap streams the snapshot as the response body.
+	mux.HandleFunc("/profile/heap", func(w http.ResponseWriter, r *http.Request) {
+		sw := &streamWriter{w: w, contentType: "application/json"}
+		if err := controller.HeapSnapshot(tabContext(r), sw); err != nil {
+			sw.fail(err)
+		}
+	})
+}
+
+func toRPCProfileSummary(s browser.CPUProfileSummary) rpc.CPUProfileSummary {
+	out := rpc.CPUProfileSummary{
+		DurationMs: ms(s.Duration),
+		Samples:    s.Samples,
+		IdleMs:     ms(s.Idle),
+		Top:        []rpc.ProfileFunction{},
+	}
+	for _, f := range s.Top {
+		out.Top = append(out.Top, rpc.ProfileFunction{
+			Function: f.Function,
+			URL:      f.URL,
+			Line:     f.Line,
+			Column:   f.Column,
+			SelfMs:   ms(f.Self),
+			Samples:  f.Samples,
+		})
+	}
+	return out
+}
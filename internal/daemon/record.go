@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+	"github.com/steipete/canvas/internal/screencast"
+)
+
+// defaultGIFFPS keeps GIFs of long sessions reasonably small.
+const defaultGIFFPS = 10
+
+func registerRecordHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/record/start", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.RecordStartRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts := browser.ScreencastOptions{
+			Quality:       req.Quality,
+			MaxWidth:      req.MaxWidth,
+			MaxHeight:     req.MaxHeight,
+			EveryNthFrame: req.EveryNthFrame,
+		}
+		if err := controller.StartScreencast(tabContext(r), opts); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		tabID, _ := controller.Screencasting()
+		rpcWriteJSON(w, http.StatusOK, rpc.RecordStartResponse{OK: true, Tab: tabID})
+	})
+
+	// /record/stop streams the encoded recording as the response body.
+	mux.HandleFunc("/record/stop", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.RecordStopRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var contentType string
+		switch req.Format {
+		case "gif":
+			contentType = "image/gif"
+		case "frames":
+			contentType = "application/x-tar"
+		default:
+			http.Error(w, fmt.Sprintf("unsupported format %q (want gif or frames)", req.Format), http.StatusBadRequest)
+			return
+		}
+
+		fps := req.FPS
+		if fps == 0 {
+			fps = defaultGIFFPS
+		}
+		// Refuse a GIF too large to encode while the recording still runs,
+		// so it can be saved as frames instead.
+		if cast, ok := controller.ScreencastSoFar(); ok && req.Format == "gif" {
+			if err := screencast.CheckGIF(toRecording(cast).Sample(fps)); err != nil {
+				http.Error(w, fmt.Sprintf("%v; still recording: save it as JPEG frames with `canvas record stop --out <dir>`, or use a lower --fps", err), http.StatusRequestEntityTooLarge)
+				return
+			}
+		}
+
+		cast, err := controller.StopScreencast(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rec := toRecording(cast)
+
+		sw := &streamWriter{w: w, contentType: contentType}
+		if req.Format == "gif" {
+			err = screencast.EncodeGIF(sw, rec.Sample(fps))
+		} else {
+			err = screencast.WriteFramesTar(sw, rec)
+		}
+		if err != nil {
+			sw.fail(err)
+		}
+	})
+}
+
+func toRecording(cast browser.Screencast) screencast.Recording {
+	rec := screencast.Recording{End: cast.End}
+	for _, f := range cast.Frames {
+		rec.Frames = append(rec.Frames, screencast.Frame{JPEG: f.Data, Time: f.Time})
+	}
+	return rec
+}
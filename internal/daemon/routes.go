@@ -0,0 +1,294 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+// routeTable holds mock routes. The first matching route answers a request.
+type routeTable struct {
+	mu     sync.Mutex
+	routes []*route
+	nextID int
+}
+
+type route struct {
+	rpc.Route
+	re *regexp.Regexp
+}
+
+// failReasons are the Network.ErrorReason values a route may fail with.
+var failReasons = []string{
+	"Failed", "Aborted", "TimedOut", "AccessDenied", "ConnectionClosed", "ConnectionReset",
+	"ConnectionRefused", "ConnectionAborted", "ConnectionFailed", "NameNotResolved",
+	"InternetDisconnected", "AddressUnreachable", "BlockedByClient", "BlockedByResponse",
+}
+
+// validateRoute checks r and fills in defaults.
+func validateRoute(r *rpc.Route) error {
+	if r.Pattern == "" {
+		return errors.New("route: missing URL pattern")
+	}
+	r.Method = strings.ToUpper(r.Method)
+	if r.Fail != "" {
+		for _, reason := range failReasons {
+			if strings.EqualFold(r.Fail, reason) {
+				r.Fail = reason
+				return nil
+			}
+		}
+		return fmt.Errorf("route: unknown failure reason %q (one of %s)", r.Fail, strings.Join(failReasons, ", "))
+	}
+	if r.Status == 0 {
+		r.Status = http.StatusOK
+	}
+	if r.Status < 100 || r.Status > 599 {
+		return fmt.Errorf("route: invalid status %d", r.Status)
+	}
+	return nil
+}
+
+// globRE compiles a URL pattern where * matches any run of characters.
+// Patterns without a '?' ignore the query string.
+func globRE(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func (r *route) matches(req browser.InterceptedRequest) bool {
+	if r.Method != "" && r.Method != req.Method {
+		return false
+	}
+	u, _, _ := strings.Cut(req.URL, "#")
+	if !strings.Contains(r.Pattern, "?") {
+		u, _, _ = strings.Cut(u, "?")
+	}
+	return r.re.MatchString(u)
+}
+
+func (t *routeTable) add(r rpc.Route) (rpc.Route, error) {
+	if err := validateRoute(&r); err != nil {
+		return rpc.Route{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	r.ID = fmt.Sprintf("r%d", t.nextID)
+	r.Hits = 0
+	t.routes = append(t.routes, &route{Route: r, re: globRE(r.Pattern)})
+	return r, nil
+}
+
+func (t *routeTable) remove(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, r := range t.routes {
+		if r.ID == id {
+			t.routes = append(t.routes[:i], t.routes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *routeTable) clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes = nil
+}
+
+func (t *routeTable) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.routes)
+}
+
+func (t *routeTable) list() []rpc.Route {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]rpc.Route, 0, len(t.routes))
+	for _, r := range t.routes {
+		out = append(out, r.Route)
+	}
+	return out
+}
+
+// match answers req from the first matching route.
+func (t *routeTable) match(req browser.InterceptedRequest) (browser.InterceptResult, bool) {
+	t.mu.Lock()
+	var hit *rpc.Route
+	for _, r := range t.routes {
+		if r.matches(req) {
+			r.Hits++
+			rr := r.Route
+			hit = &rr
+			break
+		}
+	}
+	t.mu.Unlock()
+	if hit == nil {
+		return browser.InterceptResult{}, false
+	}
+
+	res := browser.InterceptResult{Delay: time.Duration(hit.DelayMs) * time.Millisecond}
+	if hit.Fail != "" {
+		res.Fail = hit.Fail
+		return res, true
+	}
+	res.Status = hit.Status
+	res.Body = hit.Body
+	res.Headers = map[string]string{}
+	for k, v := range hit.Headers {
+		res.Headers[k] = v
+	}
+	if headerValue(res.Headers, "Content-Type") == "" && len(hit.Body) > 0 {
+		res.Headers["Content-Type"] = http.DetectContentType(hit.Body)
+	}
+	// Let cross-origin fetches read the mock.
+	if headerValue(req.Headers, "Origin") != "" && headerValue(res.Headers, "Access-Control-Allow-Origin") == "" {
+		res.Headers["Access-Control-Allow-Origin"] = "*"
+	}
+	return res, true
+}
+
+// routeSpec is one route in a routes file. Exactly one of Body, BodyFile and
+// JSON may be set; BodyFile is relative to the routes file.
+type routeSpec struct {
+	Pattern  string            `json:"pattern"`
+	Method   string            `json:"method,omitempty"`
+	Status   int64             `json:"status,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Body     string            `json:"body,omitempty"`
+	BodyFile string            `json:"body_file,omitempty"`
+	JSON     json.RawMessage   `json:"json,omitempty"`
+	Fail     string            `json:"fail,omitempty"`
+	DelayMs  int64             `json:"delay_ms,omitempty"`
+}
+
+// LoadRoutes reads a routes file: {"routes": [...]} or a bare array of
+// routes.
+func LoadRoutes(path string) ([]rpc.Route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var specs []routeSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		var file struct {
+			Routes []routeSpec `json:"routes"`
+		}
+		if err2 := json.Unmarshal(data, &file); err2 != nil {
+			return nil, fmt.Errorf("%s: %w", path, err2)
+		}
+		specs = file.Routes
+	}
+
+	out := make([]rpc.Route, 0, len(specs))
+	for i, s := range specs {
+		r := rpc.Route{Pattern: s.Pattern, Method: s.Method, Status: s.Status, Headers: s.Headers, Fail: s.Fail, DelayMs: s.DelayMs}
+		set := 0
+		if s.Body != "" {
+			r.Body = []byte(s.Body)
+			set++
+		}
+		if s.BodyFile != "" {
+			p := s.BodyFile
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(filepath.Dir(path), p)
+			}
+			if r.Body, err = os.ReadFile(p); err != nil {
+				return nil, fmt.Errorf("%s: route %d: %w", path, i+1, err)
+			}
+			if ct := mime.TypeByExtension(filepath.Ext(p)); ct != "" && headerValue(r.Headers, "Content-Type") == "" {
+				r.Headers = withHeader(r.Headers, "Content-Type", ct)
+			}
+			set++
+		}
+		if len(s.JSON) > 0 {
+			r.Body = s.JSON
+			if headerValue(r.Headers, "Content-Type") == "" {
+				r.Headers = withHeader(r.Headers, "Content-Type", "application/json")
+			}
+			set++
+		}
+		if set > 1 {
+			return nil, fmt.Errorf("%s: route %d: use only one of body, body_file and json", path, i+1)
+		}
+		if err := validateRoute(&r); err != nil {
+			return nil, fmt.Errorf("%s: route %d: %w", path, i+1, err)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func withHeader(h map[string]string, k, v string) map[string]string {
+	if h == nil {
+		h = map[string]string{}
+	}
+	h[k] = v
+	return h
+}
+
+func registerRouteHandlers(mux *http.ServeMux, interceptors *requestInterceptors) {
+	routes := interceptors.routes
+	respond := func(w http.ResponseWriter, r *http.Request) {
+		if err := interceptors.install(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.RoutesResponse{Routes: routes.list()})
+	}
+
+	mux.HandleFunc("/route/add", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.RouteAddRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := routes.add(req.Route); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		respond(w, r)
+	})
+
+	mux.HandleFunc("/route/list", func(w http.ResponseWriter, r *http.Request) {
+		rpcWriteJSON(w, http.StatusOK, rpc.RoutesResponse{Routes: routes.list()})
+	})
+
+	mux.HandleFunc("/route/remove", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.RouteRemoveRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, id := range req.IDs {
+			if !routes.remove(id) {
+				http.Error(w, fmt.Sprintf("unknown route %q (see `canvas route list`)", id), http.StatusNotFound)
+				return
+			}
+		}
+		respond(w, r)
+	})
+
+	mux.HandleFunc("/route/clear", func(w http.ResponseWriter, r *http.Request) {
+		routes.clear()
+		respond(w, r)
+	})
+}
@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func TestRouteTableMatch(t *testing.T) {
+	var table routeTable
+	mustAdd := func(r rpc.Route) rpc.Route {
+		t.Helper()
+		out, err := table.add(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	users := mustAdd(rpc.Route{Pattern: "*/api/users", Body: []byte(`[{"id":1}]`), Headers: map[string]string{"Content-Type": "application/json"}})
+	mustAdd(rpc.Route{Pattern: "*/api/save", Method: "post", Status: 500, DelayMs: 20})
+	mustAdd(rpc.Route{Pattern: "*/search?q=*", Body: []byte("found")})
+	mustAdd(rpc.Route{Pattern: "https://analytics.example/*", Fail: "blockedbyclient"})
+
+	req := func(method, url string) browser.InterceptedRequest {
+		return browser.InterceptedRequest{Method: method, URL: url}
+	}
+
+	res, ok := table.match(req("GET", "http://127.0.0.1:1/api/users?page=2#top"))
+	if !ok || res.Status != 200 || string(res.Body) != `[{"id":1}]` || res.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("users = %+v %v", res, ok)
+	}
+	if _, ok := table.match(req("GET", "http://127.0.0.1:1/api/users/1")); ok {
+		t.Fatal("pattern should match the whole URL")
+	}
+	if _, ok := table.match(req("GET", "http://127.0.0.1:1/api/save")); ok {
+		t.Fatal("method should restrict the match")
+	}
+	if res, ok := table.match(req("POST", "http://127.0.0.1:1/api/save")); !ok || res.Status != 500 || res.Delay != 20*time.Millisecond {
+		t.Fatalf("save = %+v %v", res, ok)
+	}
+	if res, ok := table.match(req("GET", "http://x/search?q=go")); !ok || res.Headers["Content-Type"] != "text/plain; charset=utf-8" {
+		t.Fatalf("search = %+v %v", res, ok)
+	}
+	if res, ok := table.match(req("GET", "https://analytics.example/collect")); !ok || res.Fail != "BlockedByClient" {
+		t.Fatalf("analytics = %+v %v", res, ok)
+	}
+
+	cors := browser.InterceptedRequest{Method: "GET", URL: "http://api.example/api/users", Headers: map[string]string{"origin": "http://127.0.0.1:1"}}
+	if res, _ := table.match(cors); res.Headers["Access-Control-Allow-Origin"] != "*" {
+		t.Fatalf("cors headers = %+v", res.Headers)
+	}
+
+	list := table.list()
+	if len(list) != 4 || list[0].ID != users.ID || list[0].Hits != 2 {
+		t.Fatalf("list = %+v", list)
+	}
+	if !table.remove(users.ID) || table.remove(users.ID) || table.len() != 3 {
+		t.Fatal("remove")
+	}
+	if _, err := table.add(rpc.Route{Pattern: "*", Fail: "Nope"}); err == nil {
+		t.Fatal("expected an error for an unknown failure reason")
+	}
+	if _, err := table.add(rpc.Route{Pattern: "*", Status: 42}); err == nil {
+		t.Fatal("expected an error for an invalid status")
+	}
+}
+
+func TestLoadRoutes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "users.json"), []byte(`[]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "routes.json")
+	write := func(s string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"routes": [
+		{"pattern": "*/api/users", "body_file": "users.json"},
+		{"pattern": "*/api/me", "json": {"name": "Ada"}},
+		{"pattern": "*/api/down", "fail": "ConnectionRefused", "delay_ms": 100}
+	]}`)
+	routes, err := LoadRoutes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 3 {
+		t.Fatalf("routes = %+v", routes)
+	}
+	if string(routes[0].Body) != "[]" || routes[0].Headers["Content-Type"] != "application/json" || routes[0].Status != 200 {
+		t.Fatalf("body_file route = %+v", routes[0])
+	}
+	if string(routes[1].Body) != `{"name": "Ada"}` || routes[1].Headers["Content-Type"] != "application/json" {
+		t.Fatalf("json route = %+v", routes[1])
+	}
+	if routes[2].Fail != "ConnectionRefused" || routes[2].DelayMs != 100 {
+		t.Fatalf("fail route = %+v", routes[2])
+	}
+
+	write(`[{"pattern": "*/a", "body": "x"}]`)
+	if routes, err := LoadRoutes(path); err != nil || len(routes) != 1 {
+		t.Fatalf("bare array = %+v, %v", routes, err)
+	}
+
+	write(`[{"pattern": "*/a", "body": "x", "json": 1}]`)
+	if _, err := LoadRoutes(path); err == nil || !strings.Contains(err.Error(), "route 1") {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func registerScreenshotHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/screenshot", func(w http.ResponseWriter, r *http.Request) {
+		opts, buf, ok := captureScreenshot(controller, w, r)
+		if !ok {
+			return
+		}
+		rpcWriteJSON(w, http.StatusOK, rpc.ScreenshotResponse{
+			Format: opts.Format,
+			Base64: base64.StdEncoding.EncodeToString(buf),
+		})
+	})
+
+	// /screenshot/raw answers with the image itself.
+	mux.HandleFunc("/screenshot/raw", func(w http.ResponseWriter, r *http.Request) {
+		opts, buf, ok := captureScreenshot(controller, w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "image/"+opts.Format)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf)
+	})
+
+	mux.HandleFunc("/screenshot/widths", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.ScreenshotWidthsRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		widths := make([]int64, len(req.Widths))
+		for i, v := range req.Widths {
+			widths[i] = int64(v)
+		}
+		opts := browser.ScreenshotOptions{Format: "png", Scale: req.Scale, OmitBackground: req.OmitBackground}
+		shots, err := controller.ScreenshotWidths(tabContext(r), widths, opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out := rpc.ScreenshotWidthsResponse{Shots: make([]rpc.WidthScreenshot, len(shots))}
+		for i, s := range shots {
+			out.Shots[i] = rpc.WidthScreenshot{Width: int(s.Width), Base64: base64.StdEncoding.EncodeToString(s.Data)}
+		}
+		rpcWriteJSON(w, http.StatusOK, out)
+	})
+}
+
+// captureScreenshot takes the screenshot a request asks for; on failure it
+// has answered the request already.
+func captureScreenshot(controller *browser.Controller, w http.ResponseWriter, r *http.Request) (browser.ScreenshotOptions, []byte, bool) {
+	var req rpc.ScreenshotRequest
+	if err := rpcReadJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return browser.ScreenshotOptions{}, nil, false
+	}
+	opts := screenshotOptions(req)
+	if err := opts.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return opts, nil, false
+	}
+	buf, err := controller.Screenshot(tabContext(r), opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return opts, nil, false
+	}
+	return opts, buf, true
+}
+
+func screenshotOptions(req rpc.ScreenshotRequest) browser.ScreenshotOptions {
+	opts := browser.ScreenshotOptions{
+		Selector:       req.Selector,
+		Format:         req.Format,
+		Quality:        req.Quality,
+		FullPage:       req.FullPage,
+		Scale:          req.Scale,
+		OmitBackground: req.OmitBackground,
+		Padding:        req.Padding,
+	}
+	if opts.Format == "" {
+		opts.Format = "png"
+	}
+	if c := req.Clip; c != nil {
+		opts.Clip = &browser.Rect{X: c.X, Y: c.Y, Width: c.Width, Height: c.Height}
+	}
+	return opts
+}
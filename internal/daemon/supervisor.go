@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// supervisedBrowser is the subset of *browser.Controller the supervisor needs.
+type supervisedBrowser interface {
+	Crashes() <-chan string
+	Relaunch(ctx context.Context) error
+	LastURL() string
+	Navigate(ctx context.Context, url string) (string, string, error)
+}
+
+// browserSupervisor relaunches the browser whenever it crashes and keeps track
+// of how often that happened.
+type browserSupervisor struct {
+	browser supervisedBrowser
+	// backoff are the waits before each relaunch attempt; the last one
+	// repeats until an attempt succeeds.
+	backoff []time.Duration
+
+	mu          sync.Mutex
+	restarts    int
+	lastCrash   string
+	lastCrashAt time.Time
+}
+
+func newBrowserSupervisor(b supervisedBrowser) *browserSupervisor {
+	return &browserSupervisor{
+		browser: b,
+		backoff: []time.Duration{0, time.Second, 3 * time.Second, 10 * time.Second, 30 * time.Second},
+	}
+}
+
+// Run blocks until ctx is done. onRelaunch is called after each successful
+// relaunch, once the tab is back on its last URL.
+func (s *browserSupervisor) Run(ctx context.Context, onRelaunch func()) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case reason := <-s.browser.Crashes():
+			lastURL := s.browser.LastURL()
+			s.mu.Lock()
+			s.lastCrash = reason
+			s.lastCrashAt = time.Now()
+			s.mu.Unlock()
+			log.Printf("browser crashed (%s); relaunching", reason)
+
+			if err := s.relaunch(ctx); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.restarts++
+			s.mu.Unlock()
+
+			if lastURL != "" {
+				if _, _, err := s.browser.Navigate(ctx, lastURL); err != nil {
+					log.Printf("navigate %s after relaunch: %v", lastURL, err)
+				}
+			}
+			if onRelaunch != nil {
+				onRelaunch()
+			}
+		}
+	}
+}
+
+// relaunch retries until the browser is back or ctx is done, in which case
+// it returns ctx's error.
+func (s *browserSupervisor) relaunch(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.backoff[min(attempt, len(s.backoff)-1)]):
+		}
+		err := s.browser.Relaunch(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Printf("browser relaunch attempt %d failed: %v", attempt+1, err)
+	}
+}
+
+func (s *browserSupervisor) Stats() (restarts int, lastCrash string, lastCrashAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.restarts, s.lastCrash, s.lastCrashAt
+}
@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeBrowser struct {
+	crashes chan string
+
+	mu         sync.Mutex
+	failFirst  int
+	relaunches int
+	navigated  []string
+}
+
+func (f *fakeBrowser) Crashes() <-chan string { return f.crashes }
+func (f *fakeBrowser) LastURL() string        { return "http://127.0.0.1:1/page" }
+
+func (f *fakeBrowser) Relaunch(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.relaunches++
+	if f.relaunches <= f.failFirst {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (f *fakeBrowser) Navigate(ctx context.Context, url string) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.navigated = append(f.navigated, url)
+	return url, "", nil
+}
+
+func TestBrowserSupervisor_RelaunchesAndNavigatesBack(t *testing.T) {
+	fb := &fakeBrowser{crashes: make(chan string, 1), failFirst: 1}
+	s := newBrowserSupervisor(fb)
+	s.backoff = []time.Duration{0, 0, 0}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	relaunched := make(chan struct{}, 1)
+	go s.Run(ctx, func() { relaunched <- struct{}{} })
+
+	fb.crashes <- "page target crashed"
+	select {
+	case <-relaunched:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for relaunch")
+	}
+
+	restarts, reason, at := s.Stats()
+	if restarts != 1 || reason != "page target crashed" || at.IsZero() {
+		t.Fatalf("stats = %d %q %v", restarts, reason, at)
+	}
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	if fb.relaunches != 2 {
+		t.Fatalf("relaunches = %d", fb.relaunches)
+	}
+	if len(fb.navigated) != 1 || fb.navigated[0] != "http://127.0.0.1:1/page" {
+		t.Fatalf("navigated = %v", fb.navigated)
+	}
+}
+
+func TestBrowserSupervisor_KeepsRetryingAfterBackoff(t *testing.T) {
+	fb := &fakeBrowser{crashes: make(chan string, 1), failFirst: 5}
+	s := newBrowserSupervisor(fb)
+	s.backoff = []time.Duration{0, time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	relaunched := make(chan struct{}, 1)
+	go s.Run(ctx, func() { relaunched <- struct{}{} })
+
+	fb.crashes <- "browser process exited"
+	select {
+	case <-relaunched:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for relaunch")
+	}
+
+	fb.mu.Lock()
+	n := fb.relaunches
+	fb.mu.Unlock()
+	if n != 6 {
+		t.Fatalf("relaunches = %d", n)
+	}
+	if restarts, reason, _ := s.Stats(); restarts != 1 || reason != "browser process exited" {
+		t.Fatalf("stats = %d %q", restarts, reason)
+	}
+}
+
+func TestBrowserSupervisor_StopsRetryingWhenDone(t *testing.T) {
+	fb := &fakeBrowser{crashes: make(chan string, 1), failFirst: 1 << 30}
+	s := newBrowserSupervisor(fb)
+	s.backoff = []time.Duration{0, time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, func() { t.Error("unexpected relaunch callback") })
+		close(done)
+	}()
+
+	fb.crashes <- "browser process exited"
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		fb.mu.Lock()
+		n := fb.relaunches
+		fb.mu.Unlock()
+		if n > 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("relaunches = %d", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	if restarts, _, _ := s.Stats(); restarts != 0 {
+		t.Fatalf("restarts = %d", restarts)
+	}
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/steipete/canvas/internal/browser"
+	"github.com/steipete/canvas/internal/rpc"
+)
+
+func registerTraceHandlers(mux *http.ServeMux, controller *browser.Controller) {
+	mux.HandleFunc("/trace/start", func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.TraceStartRequest
+		if err := rpcReadJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := controller.StartTrace(tabContext(r), req.Categories); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		tabID, _ := controller.Tracing()
+		rpcWriteJSON(w, http.StatusOK, rpc.TraceStartResponse{OK: true, Tab: tabID})
+	})
+
+	// /trace/stop streams the trace JSON as the response body.
+	mux.HandleFunc("/trace/stop", func(w http.ResponseWriter, r *http.Request) {
+		sw := &streamWriter{w: w, contentType: "application/json"}
+		if err := controller.StopTrace(r.Context(), sw); err != nil {
+			sw.fail(err)
+		}
+	})
+}
+
+// streamWriter sends a 200 with the first byte of a streamed body, so errors
+// before that can still become a proper error response.
+type streamWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", s.contentType)
+		s.w.WriteHeader(http.StatusOK)
+	}
+	return s.w.Write(p)
+}
+
+// fail reports err: as an error response if nothing was sent yet, otherwise
+// by aborting the connection so the client doesn't take a truncated body for
+// a complete one.
+func (s *streamWriter) fail(err error) {
+	if !s.started {
+		http.Error(s.w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	panic(http.ErrAbortHandler)
+}
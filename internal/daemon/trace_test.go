@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sw := &streamWriter{w: rec, contentType: "application/json"}
+	sw.fail(errors.New("no trace running"))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "no trace running\n" {
+		t.Fatalf("early failure = %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	sw = &streamWriter{w: rec, contentType: "application/json"}
+	if _, err := sw.Write([]byte(`{"traceEvents":[`)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("stream = %d %v", rec.Code, rec.Header())
+	}
+	defer func() {
+		if recover() != http.ErrAbortHandler {
+			t.Fatal("a failure mid-stream should abort the response")
+		}
+	}()
+	sw.fail(errors.New("read failed"))
+}
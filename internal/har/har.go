@@ -0,0 +1,224 @@
+// Package har reads and writes HTTP Archive (HAR 1.2) files and looks up
+// recorded responses for replay.
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Pages   []Page  `json:"pages,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Page struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	PageTimings     PageTimings `json:"pageTimings"`
+}
+
+type PageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad,omitempty"`
+	OnLoad        float64 `json:"onLoad,omitempty"`
+}
+
+type Entry struct {
+	Pageref         string    `json:"pageref,omitempty"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         Request   `json:"request"`
+	Response        Response  `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         Timings   `json:"timings"`
+	ServerIPAddress string    `json:"serverIPAddress,omitempty"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int64       `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"` // "base64" for binary bodies
+}
+
+// Timings are in milliseconds; -1 marks phases that don't apply.
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// New returns an empty archive.
+func New(creator, version string) *HAR {
+	return &HAR{Log: Log{Version: "1.2", Creator: Creator{Name: creator, Version: version}, Entries: []Entry{}}}
+}
+
+// Parse decodes and sanity-checks a HAR file.
+func Parse(data []byte) (*HAR, error) {
+	var h HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parse har: %w", err)
+	}
+	if h.Log.Version == "" && len(h.Log.Entries) == 0 {
+		return nil, errors.New("parse har: missing log.entries")
+	}
+	return &h, nil
+}
+
+// Body returns the decoded response body.
+func (c Content) Body() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+	return []byte(c.Text), nil
+}
+
+// SetBody stores body as text, or base64 when it isn't valid UTF-8 text.
+func (c *Content) SetBody(body []byte) {
+	c.Size = int64(len(body))
+	if isText(c.MimeType, body) {
+		c.Text, c.Encoding = string(body), ""
+		return
+	}
+	c.Text, c.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func isText(mimeType string, body []byte) bool {
+	mt := strings.ToLower(mimeType)
+	textual := strings.HasPrefix(mt, "text/") || strings.Contains(mt, "json") ||
+		strings.Contains(mt, "javascript") || strings.Contains(mt, "xml") || strings.Contains(mt, "svg")
+	return textual && utf8.Valid(body)
+}
+
+// QueryString splits the query of rawURL into HAR name/value pairs.
+func QueryString(rawURL string) []NameValue {
+	out := []NameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return out
+	}
+	for _, kv := range strings.Split(u.RawQuery, "&") {
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		k, _ = url.QueryUnescape(k)
+		v, _ = url.QueryUnescape(v)
+		out = append(out, NameValue{Name: k, Value: v})
+	}
+	return out
+}
+
+// Find returns the recorded entry for a request: same method and URL
+// (ignoring the fragment), preferring an entry with the same post body.
+// Otherwise the last entry that got a response wins.
+func (h *HAR) Find(method, rawURL string, postData []byte) (*Entry, bool) {
+	key := stripFragment(rawURL)
+	var best *Entry
+	for i := range h.Log.Entries {
+		e := &h.Log.Entries[i]
+		if !strings.EqualFold(e.Request.Method, method) || stripFragment(e.Request.URL) != key {
+			continue
+		}
+		if e.Request.PostData != nil && len(postData) > 0 && e.Request.PostData.Text == string(postData) {
+			return e, true
+		}
+		if best == nil || e.Response.Status != 0 {
+			best = e
+		}
+	}
+	return best, best != nil
+}
+
+func stripFragment(u string) string {
+	u, _, _ = strings.Cut(u, "#")
+	return u
+}
+
+// Headers converts a header map into sorted HAR name/value pairs. Multi-value
+// headers (newline-separated, as Chrome reports them) become several pairs.
+func Headers(h map[string]string) []NameValue {
+	out := []NameValue{}
+	for k, v := range h {
+		for _, line := range strings.Split(v, "\n") {
+			out = append(out, NameValue{Name: k, Value: line})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	return out
+}
+
+// HeaderMap is the inverse of Headers.
+func HeaderMap(nv []NameValue) map[string]string {
+	out := make(map[string]string, len(nv))
+	for _, h := range nv {
+		if prev, ok := out[h.Name]; ok {
+			out[h.Name] = prev + "\n" + h.Value
+			continue
+		}
+		out[h.Name] = h.Value
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package har
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestContentBodyRoundTrip(t *testing.T) {
+	var c Content
+	c.MimeType = "application/json"
+	c.SetBody([]byte(`{"ok":true}`))
+	if c.Encoding != "" || c.Text != `{"ok":true}` || c.Size != 11 {
+		t.Fatalf("text content = %+v", c)
+	}
+
+	bin := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+	c = Content{MimeType: "image/png"}
+	c.SetBody(bin)
+	if c.Encoding != "base64" {
+		t.Fatalf("binary content = %+v", c)
+	}
+	got, err := c.Body()
+	if err != nil || !reflect.DeepEqual(got, bin) {
+		t.Fatalf("body = %v, %v", got, err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	h := New("canvas", "test")
+	add := func(method, url, post string, status int64, body string) {
+		e := Entry{Request: Request{Method: method, URL: url}, Response: Response{Status: status, Content: Content{Text: body}}}
+		if post != "" {
+			e.Request.PostData = &PostData{Text: post}
+		}
+		h.Log.Entries = append(h.Log.Entries, e)
+	}
+	add("GET", "http://x/api/items", "", 200, "first")
+	add("GET", "http://x/api/items", "", 200, "second")
+	add("GET", "http://x/api/items", "", 0, "aborted")
+	add("POST", "http://x/api/search", `{"q":"a"}`, 200, "a")
+	add("POST", "http://x/api/search", `{"q":"b"}`, 200, "b")
+
+	if e, ok := h.Find("GET", "http://x/api/items#frag", nil); !ok || e.Response.Content.Text != "second" {
+		t.Fatalf("GET = %+v %v", e, ok)
+	}
+	if e, ok := h.Find("POST", "http://x/api/search", []byte(`{"q":"a"}`)); !ok || e.Response.Content.Text != "a" {
+		t.Fatalf("POST a = %+v %v", e, ok)
+	}
+	if e, ok := h.Find("POST", "http://x/api/search", []byte(`{"q":"zzz"}`)); !ok || e.Response.Content.Text != "b" {
+		t.Fatalf("POST fallback = %+v %v", e, ok)
+	}
+	if _, ok := h.Find("GET", "http://x/api/other", nil); ok {
+		t.Fatal("unexpected match")
+	}
+}
+
+func TestParse(t *testing.T) {
+	h := New("canvas", "test")
+	h.Log.Entries = append(h.Log.Entries, Entry{Request: Request{Method: "GET", URL: "http://x/?a=1&b=two%20words"}})
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := Parse(data)
+	if err != nil || len(back.Log.Entries) != 1 || back.Log.Version != "1.2" {
+		t.Fatalf("parse = %+v, %v", back, err)
+	}
+	if _, err := Parse([]byte(`{"foo":1}`)); err == nil {
+		t.Fatal("expected error for a non-HAR document")
+	}
+
+	qs := QueryString("http://x/?a=1&b=two%20words")
+	if !reflect.DeepEqual(qs, []NameValue{{"a", "1"}, {"b", "two words"}}) {
+		t.Fatalf("query = %+v", qs)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	in := map[string]string{"Set-Cookie": "a=1\nb=2", "content-type": "text/html"}
+	nv := Headers(in)
+	want := []NameValue{{"content-type", "text/html"}, {"Set-Cookie", "a=1"}, {"Set-Cookie", "b=2"}}
+	if !reflect.DeepEqual(nv, want) {
+		t.Fatalf("headers = %+v", nv)
+	}
+	if back := HeaderMap(nv); !reflect.DeepEqual(back, in) {
+		t.Fatalf("header map = %+v", back)
+	}
+}
@@ -0,0 +1,199 @@
+// Package imgdiff compares two images pixel by pixel, in the manner of
+// pixelmatch: colors are compared in YIQ space, and pixels that only differ
+// through anti-aliasing can be ignored.
+package imgdiff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Options configure Compare.
+type Options struct {
+	// Threshold is the color distance (0 to 1) under which pixels count as
+	// equal; pixelmatch's default is 0.1.
+	Threshold float64
+	// IncludeAA counts anti-aliased pixels as differences.
+	IncludeAA bool
+}
+
+// Result is the outcome of Compare. Images of different sizes are compared
+// on the larger canvas; pixels outside either image count as different.
+type Result struct {
+	Width, Height int
+	Diff          int // differing pixels
+	AntiAliased   int // pixels ignored as anti-aliasing
+	SizeMismatch  bool
+	// Image shows a, faded, with differences in red and ignored
+	// anti-aliasing in yellow.
+	Image *image.NRGBA
+}
+
+// Ratio is the share of differing pixels.
+func (r Result) Ratio() float64 {
+	if r.Width*r.Height == 0 {
+		return 0
+	}
+	return float64(r.Diff) / float64(r.Width*r.Height)
+}
+
+var (
+	diffColor = color.NRGBA{255, 0, 0, 255}
+	aaColor   = color.NRGBA{255, 255, 0, 255}
+)
+
+// Compare compares a (the baseline) with b.
+func Compare(a, b image.Image, opts Options) Result {
+	img1, img2 := toNRGBA(a), toNRGBA(b)
+	w1, h1 := img1.Rect.Dx(), img1.Rect.Dy()
+	w2, h2 := img2.Rect.Dx(), img2.Rect.Dy()
+	res := Result{
+		Width:        max(w1, w2),
+		Height:       max(h1, h2),
+		SizeMismatch: w1 != w2 || h1 != h2,
+	}
+	res.Image = image.NewNRGBA(image.Rect(0, 0, res.Width, res.Height))
+	maxDelta := 35215 * opts.Threshold * opts.Threshold
+
+	for y := range res.Height {
+		for x := range res.Width {
+			if x >= w1 || y >= h1 || x >= w2 || y >= h2 {
+				res.Diff++
+				res.Image.SetNRGBA(x, y, diffColor)
+				continue
+			}
+			delta := colorDelta(img1, img2, x, y, x, y, false)
+			switch {
+			case math.Abs(delta) <= maxDelta:
+				res.Image.SetNRGBA(x, y, faded(img1, x, y))
+			case !opts.IncludeAA && (antialiased(img1, img2, x, y) || antialiased(img2, img1, x, y)):
+				res.AntiAliased++
+				res.Image.SetNRGBA(x, y, aaColor)
+			default:
+				res.Diff++
+				res.Image.SetNRGBA(x, y, diffColor)
+			}
+		}
+	}
+	return res
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out
+}
+
+// rgb returns the pixel blended onto white, as pixelmatch does.
+func rgb(img *image.NRGBA, x, y int) (r, g, b float64) {
+	i := img.PixOffset(x, y)
+	a := float64(img.Pix[i+3]) / 255
+	blend := func(c uint8) float64 { return 255 + (float64(c)-255)*a }
+	return blend(img.Pix[i]), blend(img.Pix[i+1]), blend(img.Pix[i+2])
+}
+
+func yiqY(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+
+// colorDelta is the squared YIQ distance of two pixels, negative if the
+// second is brighter. yOnly compares brightness alone.
+func colorDelta(img1, img2 *image.NRGBA, x1, y1, x2, y2 int, yOnly bool) float64 {
+	r1, g1, b1 := rgb(img1, x1, y1)
+	r2, g2, b2 := rgb(img2, x2, y2)
+	if r1 == r2 && g1 == g2 && b1 == b2 {
+		return 0
+	}
+	yy1, yy2 := yiqY(r1, g1, b1), yiqY(r2, g2, b2)
+	y := yy1 - yy2
+	if yOnly {
+		return y
+	}
+	i := r1*0.59597799 - g1*0.27417610 - b1*0.32180189 - (r2*0.59597799 - g2*0.27417610 - b2*0.32180189)
+	q := r1*0.21147017 - g1*0.52261711 + b1*0.31114694 - (r2*0.21147017 - g2*0.52261711 + b2*0.31114694)
+	delta := 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+	if yy1 > yy2 {
+		return -delta
+	}
+	return delta
+}
+
+// antialiased reports whether the pixel of img looks like an anti-aliased
+// edge: its neighbours run from darker to brighter, and the darkest or the
+// brightest neighbour sits in a flat area in both images.
+func antialiased(img, other *image.NRGBA, x1, y1 int) bool {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	x0, y0 := max(x1-1, 0), max(y1-1, 0)
+	x2, y2 := min(x1+1, w-1), min(y1+1, h-1)
+	zeroes := 0
+	if x1 == x0 || x1 == x2 || y1 == y0 || y1 == y2 {
+		zeroes = 1
+	}
+	var minD, maxD float64
+	var minX, minY, maxX, maxY int
+	for x := x0; x <= x2; x++ {
+		for y := y0; y <= y2; y++ {
+			if x == x1 && y == y1 {
+				continue
+			}
+			delta := colorDelta(img, img, x1, y1, x, y, true)
+			switch {
+			case delta == 0:
+				zeroes++
+				if zeroes > 2 {
+					return false
+				}
+			case delta < minD:
+				minD, minX, minY = delta, x, y
+			case delta > maxD:
+				maxD, maxX, maxY = delta, x, y
+			}
+		}
+	}
+	if minD == 0 || maxD == 0 {
+		return false
+	}
+	return (flat(img, minX, minY) && flat(other, minX, minY)) ||
+		(flat(img, maxX, maxY) && flat(other, maxX, maxY))
+}
+
+// flat reports whether at least three neighbours have the pixel's color.
+func flat(img *image.NRGBA, x1, y1 int) bool {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if x1 >= w || y1 >= h {
+		return false
+	}
+	x0, y0 := max(x1-1, 0), max(y1-1, 0)
+	x2, y2 := min(x1+1, w-1), min(y1+1, h-1)
+	zeroes := 0
+	if x1 == x0 || x1 == x2 || y1 == y0 || y1 == y2 {
+		zeroes = 1
+	}
+	c := img.PixOffset(x1, y1)
+	for x := x0; x <= x2; x++ {
+		for y := y0; y <= y2; y++ {
+			if x == x1 && y == y1 {
+				continue
+			}
+			o := img.PixOffset(x, y)
+			if img.Pix[o] == img.Pix[c] && img.Pix[o+1] == img.Pix[c+1] && img.Pix[o+2] == img.Pix[c+2] && img.Pix[o+3] == img.Pix[c+3] {
+				zeroes++
+			}
+			if zeroes > 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// faded is the pixel as a light grey, for context in the diff image.
+func faded(img *image.NRGBA, x, y int) color.NRGBA {
+	r, g, b := rgb(img, x, y)
+	v := uint8(255 + (yiqY(r, g, b)-255)*0.1)
+	return color.NRGBA{v, v, v, 255}
+}
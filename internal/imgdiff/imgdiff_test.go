@@ -0,0 +1,87 @@
+package imgdiff
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func filled(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+var (
+	white = color.NRGBA{255, 255, 255, 255}
+	black = color.NRGBA{0, 0, 0, 255}
+)
+
+func TestCompareIdentical(t *testing.T) {
+	a := filled(20, 10, white)
+	res := Compare(a, filled(20, 10, white), Options{Threshold: 0.1})
+	if res.Diff != 0 || res.AntiAliased != 0 || res.SizeMismatch || res.Ratio() != 0 {
+		t.Fatalf("result = %+v", res)
+	}
+	if got := res.Image.NRGBAAt(3, 3); got.R != 255 || got.R != got.G {
+		t.Fatalf("unchanged pixel drawn as %v", got)
+	}
+}
+
+func TestCompareThreshold(t *testing.T) {
+	a := filled(10, 10, white)
+	b := filled(10, 10, white)
+	b.SetNRGBA(5, 5, color.NRGBA{250, 250, 250, 255}) // barely different
+	b.SetNRGBA(1, 1, black)
+	b.SetNRGBA(1, 2, black)
+	b.SetNRGBA(2, 1, black)
+	b.SetNRGBA(2, 2, black)
+
+	res := Compare(a, b, Options{Threshold: 0.1})
+	if res.Diff != 4 {
+		t.Fatalf("diff = %d, want 4", res.Diff)
+	}
+	if res.Image.NRGBAAt(1, 1) != diffColor {
+		t.Fatalf("diff pixel drawn as %v", res.Image.NRGBAAt(1, 1))
+	}
+	if res := Compare(a, b, Options{Threshold: 0}); res.Diff != 5 {
+		t.Fatalf("diff at threshold 0 = %d, want 5", res.Diff)
+	}
+}
+
+func TestCompareAntiAliasing(t *testing.T) {
+	// A black/white edge whose boundary pixel is grey in one image only.
+	edge := func(grey uint8) *image.NRGBA {
+		img := filled(10, 10, white)
+		for y := range 10 {
+			for x := range 4 {
+				img.SetNRGBA(x, y, black)
+			}
+			img.SetNRGBA(4, y, color.NRGBA{grey, grey, grey, 255})
+		}
+		return img
+	}
+	a, b := edge(255), edge(128)
+
+	res := Compare(a, b, Options{Threshold: 0.1})
+	if res.Diff != 0 || res.AntiAliased != 10 {
+		t.Fatalf("result: diff %d, aa %d", res.Diff, res.AntiAliased)
+	}
+	if res := Compare(a, b, Options{Threshold: 0.1, IncludeAA: true}); res.Diff != 10 {
+		t.Fatalf("diff with IncludeAA = %d", res.Diff)
+	}
+}
+
+func TestCompareSizeMismatch(t *testing.T) {
+	res := Compare(filled(10, 10, white), filled(10, 12, white), Options{Threshold: 0.1})
+	if !res.SizeMismatch || res.Width != 10 || res.Height != 12 || res.Diff != 20 {
+		t.Fatalf("result = %+v", res)
+	}
+	if res.Ratio() != 20.0/120 {
+		t.Fatalf("ratio = %v", res.Ratio())
+	}
+}
@@ -5,15 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// TabHeader selects the tab a request targets; empty means the active tab.
+const TabHeader = "X-Canvas-Tab"
+
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	token      string
+	tab        string
 }
 
 func NewUnixClient(socketPath, token string) *Client {
@@ -33,12 +39,54 @@ func NewUnixClient(socketPath, token string) *Client {
 	}
 }
 
+// WithTab returns a copy of the client whose requests target the given tab
+// without switching the active one.
+func (c *Client) WithTab(id string) *Client {
+	cp := *c
+	cp.tab = id
+	return &cp
+}
+
+// WithTimeout returns a copy of the client for calls that may outlast the
+// default 30s HTTP timeout (e.g. repeated page loads).
+func (c *Client) WithTimeout(d time.Duration) *Client {
+	cp := *c
+	hc := *c.httpClient
+	hc.Timeout = d
+	cp.httpClient = &hc
+	return &cp
+}
+
 func (c *Client) doJSON(ctx context.Context, method, path string, reqBody any, out any) error {
+	resp, err := c.do(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// doRaw streams a non-JSON response body (e.g. a trace) into w.
+func (c *Client) doRaw(ctx context.Context, method, path string, reqBody any, w io.Writer) error {
+	resp, err := c.do(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// do sends a request and returns the response if it succeeded.
+func (c *Client) do(ctx context.Context, method, path string, reqBody any) (*http.Response, error) {
 	var body *bytes.Reader
 	if reqBody != nil {
 		b, err := json.Marshal(reqBody)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		body = bytes.NewReader(b)
 	} else {
@@ -47,7 +95,7 @@ func (c *Client) doJSON(ctx context.Context, method, path string, reqBody any, o
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if reqBody != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -55,20 +103,41 @@ func (c *Client) doJSON(ctx context.Context, method, path string, reqBody any, o
 	if c.token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
+	if c.tab != "" {
+		req.Header.Set(TabHeader, c.tab)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
-
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("%s %s failed: %s", method, path, resp.Status)
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{
+			Method:     method,
+			Path:       path,
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(msg)),
+		}
 	}
-	if out == nil {
-		return nil
+	return resp, nil
+}
+
+// StatusError is a non-2xx answer from the daemon.
+type StatusError struct {
+	Method, Path string
+	Status       string
+	StatusCode   int
+	Message      string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s failed: %s: %s", e.Method, e.Path, e.Status, e.Message)
 	}
-	return json.NewDecoder(resp.Body).Decode(out)
+	return fmt.Sprintf("%s %s failed: %s", e.Method, e.Path, e.Status)
 }
 
 func (c *Client) Status(ctx context.Context) (StatusResponse, error) {
@@ -131,9 +200,222 @@ func (c *Client) DomWait(ctx context.Context, selector, state string, timeoutMS
 	return out, err
 }
 
-func (c *Client) Screenshot(ctx context.Context, selector string) (ScreenshotResponse, error) {
+func (c *Client) Screenshot(ctx context.Context, req ScreenshotRequest) (ScreenshotResponse, error) {
+	if req.Format == "" {
+		req.Format = "png"
+	}
 	var out ScreenshotResponse
-	err := c.doJSON(ctx, http.MethodPost, "/screenshot", ScreenshotRequest{Selector: selector, Format: "png"}, &out)
+	err := c.doJSON(ctx, http.MethodPost, "/screenshot", req, &out)
+	return out, err
+}
+
+// ScreenshotTo captures a screenshot and streams the image into w, without
+// the base64 round trip of Screenshot.
+func (c *Client) ScreenshotTo(ctx context.Context, req ScreenshotRequest, w io.Writer) error {
+	if req.Format == "" {
+		req.Format = "png"
+	}
+	return c.doRaw(ctx, http.MethodPost, "/screenshot/raw", req, w)
+}
+
+func (c *Client) ScreenshotWidths(ctx context.Context, req ScreenshotWidthsRequest) (ScreenshotWidthsResponse, error) {
+	var out ScreenshotWidthsResponse
+	err := c.doJSON(ctx, http.MethodPost, "/screenshot/widths", req, &out)
+	return out, err
+}
+
+func (c *Client) Tabs(ctx context.Context) (TabsResponse, error) {
+	var out TabsResponse
+	err := c.doJSON(ctx, http.MethodGet, "/tabs", nil, &out)
+	return out, err
+}
+
+func (c *Client) TabNew(ctx context.Context, url string) (TabResponse, error) {
+	var out TabResponse
+	err := c.doJSON(ctx, http.MethodPost, "/tabs/new", TabNewRequest{URL: url}, &out)
+	return out, err
+}
+
+func (c *Client) TabSwitch(ctx context.Context, id string) (TabResponse, error) {
+	var out TabResponse
+	err := c.doJSON(ctx, http.MethodPost, "/tabs/switch", TabRequest{ID: id}, &out)
+	return out, err
+}
+
+func (c *Client) TabClose(ctx context.Context, id string) (TabsResponse, error) {
+	var out TabsResponse
+	err := c.doJSON(ctx, http.MethodPost, "/tabs/close", TabRequest{ID: id}, &out)
+	return out, err
+}
+
+func (c *Client) EmulateDevice(ctx context.Context, req EmulateDeviceRequest) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/emulate/device", req, &out)
+	return out, err
+}
+
+func (c *Client) EmulateMedia(ctx context.Context, req EmulateMediaRequest) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/emulate/media", req, &out)
+	return out, err
+}
+
+func (c *Client) EmulateLocale(ctx context.Context, locale string) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/emulate/locale", EmulateLocaleRequest{Locale: locale}, &out)
+	return out, err
+}
+
+func (c *Client) EmulateTimezone(ctx context.Context, tz string) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/emulate/timezone", EmulateTimezoneRequest{Timezone: tz}, &out)
+	return out, err
+}
+
+func (c *Client) EmulateGeolocation(ctx context.Context, g Geolocation) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/emulate/geolocation", EmulateGeolocationRequest{Geolocation: g}, &out)
+	return out, err
+}
+
+func (c *Client) NetworkThrottle(ctx context.Context, req NetworkThrottleRequest) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/network/throttle", req, &out)
+	return out, err
+}
+
+func (c *Client) NetworkLog(ctx context.Context, req NetworkLogRequest) (NetworkLogResponse, error) {
+	var out NetworkLogResponse
+	err := c.doJSON(ctx, http.MethodPost, "/network/log", req, &out)
+	return out, err
+}
+
+func (c *Client) NetworkBody(ctx context.Context, id string) (NetworkBodyResponse, error) {
+	var out NetworkBodyResponse
+	err := c.doJSON(ctx, http.MethodPost, "/network/body", NetworkBodyRequest{ID: id}, &out)
+	return out, err
+}
+
+func (c *Client) EmulateClear(ctx context.Context, what string) (EmulationResponse, error) {
+	var out EmulationResponse
+	err := c.doJSON(ctx, http.MethodPost, "/emulate/clear", EmulateClearRequest{What: what}, &out)
+	return out, err
+}
+
+func (c *Client) Logs(ctx context.Context, req LogsRequest) (LogsResponse, error) {
+	var out LogsResponse
+	err := c.doJSON(ctx, http.MethodPost, "/logs", req, &out)
+	return out, err
+}
+
+func (c *Client) HARStart(ctx context.Context) (HARStartResponse, error) {
+	var out HARStartResponse
+	err := c.doJSON(ctx, http.MethodPost, "/har/start", nil, &out)
+	return out, err
+}
+
+func (c *Client) HARStop(ctx context.Context) (HARStopResponse, error) {
+	var out HARStopResponse
+	err := c.doJSON(ctx, http.MethodPost, "/har/stop", nil, &out)
+	return out, err
+}
+
+func (c *Client) HARReplay(ctx context.Context, req HARReplayRequest) (HARReplayResponse, error) {
+	var out HARReplayResponse
+	err := c.doJSON(ctx, http.MethodPost, "/har/replay", req, &out)
+	return out, err
+}
+
+func (c *Client) RouteAdd(ctx context.Context, r Route) (RoutesResponse, error) {
+	var out RoutesResponse
+	err := c.doJSON(ctx, http.MethodPost, "/route/add", RouteAddRequest{Route: r}, &out)
+	return out, err
+}
+
+func (c *Client) RouteList(ctx context.Context) (RoutesResponse, error) {
+	var out RoutesResponse
+	err := c.doJSON(ctx, http.MethodPost, "/route/list", nil, &out)
+	return out, err
+}
+
+func (c *Client) RouteRemove(ctx context.Context, ids []string) (RoutesResponse, error) {
+	var out RoutesResponse
+	err := c.doJSON(ctx, http.MethodPost, "/route/remove", RouteRemoveRequest{IDs: ids}, &out)
+	return out, err
+}
+
+func (c *Client) RouteClear(ctx context.Context) (RoutesResponse, error) {
+	var out RoutesResponse
+	err := c.doJSON(ctx, http.MethodPost, "/route/clear", nil, &out)
+	return out, err
+}
+
+func (c *Client) Perf(ctx context.Context, req PerfRequest) (PerfResponse, error) {
+	var out PerfResponse
+	err := c.doJSON(ctx, http.MethodPost, "/perf", req, &out)
+	return out, err
+}
+
+func (c *Client) TraceStart(ctx context.Context, categories []string) (TraceStartResponse, error) {
+	var out TraceStartResponse
+	err := c.doJSON(ctx, http.MethodPost, "/trace/start", TraceStartRequest{Categories: categories}, &out)
+	return out, err
+}
+
+// TraceStop ends the running trace and streams its JSON into w.
+func (c *Client) TraceStop(ctx context.Context, w io.Writer) error {
+	return c.doRaw(ctx, http.MethodPost, "/trace/stop", nil, w)
+}
+
+func (c *Client) ProfileCPU(ctx context.Context, req ProfileCPURequest) (ProfileCPUResponse, error) {
+	var out ProfileCPUResponse
+	err := c.doJSON(ctx, http.MethodPost, "/profile/cpu", req, &out)
+	return out, err
+}
+
+// ProfileHeap takes a heap snapshot and streams it into w.
+func (c *Client) ProfileHeap(ctx context.Context, w io.Writer) error {
+	return c.doRaw(ctx, http.MethodPost, "/profile/heap", nil, w)
+}
+
+func (c *Client) CoverageStart(ctx context.Context) (CoverageStartResponse, error) {
+	var out CoverageStartResponse
+	err := c.doJSON(ctx, http.MethodPost, "/coverage/start", nil, &out)
+	return out, err
+}
+
+func (c *Client) CoverageStop(ctx context.Context) (CoverageReport, error) {
+	var out CoverageReport
+	err := c.doJSON(ctx, http.MethodPost, "/coverage/stop", nil, &out)
+	return out, err
+}
+
+func (c *Client) PDF(ctx context.Context, req PDFRequest) (PDFResponse, error) {
+	var out PDFResponse
+	err := c.doJSON(ctx, http.MethodPost, "/pdf", req, &out)
+	return out, err
+}
+
+func (c *Client) RecordStart(ctx context.Context, req RecordStartRequest) (RecordStartResponse, error) {
+	var out RecordStartResponse
+	err := c.doJSON(ctx, http.MethodPost, "/record/start", req, &out)
+	return out, err
+}
+
+// RecordStop ends the screencast and streams it into w in req.Format.
+func (c *Client) RecordStop(ctx context.Context, req RecordStopRequest, w io.Writer) error {
+	return c.doRaw(ctx, http.MethodPost, "/record/stop", req, w)
+}
+
+func (c *Client) A11yTree(ctx context.Context, req A11yTreeRequest) (A11yTreeResponse, error) {
+	var out A11yTreeResponse
+	err := c.doJSON(ctx, http.MethodPost, "/a11y/tree", req, &out)
+	return out, err
+}
+
+func (c *Client) A11yAudit(ctx context.Context) (A11yAuditResponse, error) {
+	var out A11yAuditResponse
+	err := c.doJSON(ctx, http.MethodPost, "/a11y/audit", nil, &out)
 	return out, err
 }
 
@@ -50,3 +50,40 @@ func TestClientStatus_DevToolsFields(t *testing.T) {
 		t.Fatalf("missing devtools ws url")
 	}
 }
+
+func TestClientWithTab_SetsHeader(t *testing.T) {
+	socketPath := shortSocketPath(t)
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var gotTabs []string
+	h := NewHandler("token123")
+	h.Mux.HandleFunc("/eval", func(w http.ResponseWriter, r *http.Request) {
+		gotTabs = append(gotTabs, r.Header.Get(TabHeader))
+		_ = json.NewEncoder(w).Encode(EvalResponse{Value: 1})
+	})
+
+	srv := &http.Server{Handler: h}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+
+	c := NewUnixClient(socketPath, "token123")
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := c.WithTab("ABC123").Eval(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Eval(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(gotTabs) != 2 || gotTabs[0] != "ABC123" || gotTabs[1] != "" {
+		t.Fatalf("tab headers = %q", gotTabs)
+	}
+}
@@ -137,7 +137,7 @@ func TestClientMethods_RequestShapes(t *testing.T) {
 	if _, err := c.DomWait(ctx, "#x", "visible", 123); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := c.Screenshot(ctx, ""); err != nil {
+	if _, err := c.Screenshot(ctx, ScreenshotRequest{}); err != nil {
 		t.Fatal(err)
 	}
 	if _, err := c.Stop(ctx); err != nil {
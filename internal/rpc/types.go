@@ -1,20 +1,39 @@
 package rpc
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type StatusResponse struct {
-	Running       bool   `json:"running"`
-	BrowserAlive  bool   `json:"browser_alive"`
-	PID           int    `json:"pid,omitempty"`
-	Dir           string `json:"dir,omitempty"`
-	HTTPAddr      string `json:"http_addr,omitempty"`
-	HTTPPort      int    `json:"http_port,omitempty"`
-	CurrentURL    string `json:"current_url,omitempty"`
-	Title         string `json:"title,omitempty"`
-	Headless      bool   `json:"headless,omitempty"`
-	BrowserPID    int    `json:"browser_pid,omitempty"`
-	DevToolsPort  int    `json:"devtools_port,omitempty"`
-	DevToolsWSURL string `json:"devtools_ws_url,omitempty"`
-	BrowserBinary string `json:"browser_bin,omitempty"`
-	Error         string `json:"error,omitempty"`
+	Running       bool       `json:"running"`
+	Session       string     `json:"session,omitempty"`
+	BrowserAlive  bool       `json:"browser_alive"`
+	PID           int        `json:"pid,omitempty"`
+	Dir           string     `json:"dir,omitempty"`
+	HTTPAddr      string     `json:"http_addr,omitempty"`
+	HTTPPort      int        `json:"http_port,omitempty"`
+	CurrentURL    string     `json:"current_url,omitempty"`
+	Title         string     `json:"title,omitempty"`
+	Headless      bool       `json:"headless,omitempty"`
+	Attached      bool       `json:"attached,omitempty"`
+	BrowserPID    int        `json:"browser_pid,omitempty"`
+	DevToolsPort  int        `json:"devtools_port,omitempty"`
+	DevToolsWSURL string     `json:"devtools_ws_url,omitempty"`
+	BrowserBinary string     `json:"browser_bin,omitempty"`
+	Restarts      int        `json:"browser_restarts,omitempty"`
+	LastCrash     string     `json:"last_crash_reason,omitempty"`
+	LastCrashAt   time.Time  `json:"last_crash_at,omitzero"`
+	ActiveTab     string     `json:"active_tab,omitempty"`
+	Tabs          []TabInfo  `json:"tabs,omitempty"`
+	Emulation     *Emulation `json:"emulation,omitempty"`
+	HARRecording  bool       `json:"har_recording,omitempty"`
+	HARReplay     int        `json:"har_replay_entries,omitempty"` // entries being replayed
+	Routes        int        `json:"routes,omitempty"`
+	Tracing       string     `json:"tracing,omitempty"`    // tab being traced
+	Coverage      string     `json:"coverage,omitempty"`   // tab whose coverage is recorded
+	Screencast    string     `json:"screencast,omitempty"` // tab being recorded
+	Error         string     `json:"error,omitempty"`
 }
 
 type GotoRequest struct {
@@ -24,6 +43,27 @@ type GotoRequest struct {
 type GotoResponse struct {
 	URL   string `json:"url"`
 	Title string `json:"title,omitempty"`
+	LoadReport
+}
+
+// LoadReport describes problems observed while a page loaded.
+type LoadReport struct {
+	Status         int64           `json:"status,omitempty"` // HTTP status of the main document
+	FailedRequests []FailedRequest `json:"failed_requests,omitempty"`
+	Errors         []LogEntry      `json:"errors,omitempty"` // uncaught exceptions and console errors
+}
+
+// HasErrors reports whether the load produced an error status, failed
+// requests or page errors.
+func (r LoadReport) HasErrors() bool {
+	return r.Status >= 400 || len(r.FailedRequests) > 0 || len(r.Errors) > 0
+}
+
+type FailedRequest struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`
+	Status int64  `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
 type EvalRequest struct {
@@ -35,7 +75,10 @@ type EvalResponse struct {
 }
 
 type ReloadResponse struct {
-	OK bool `json:"ok"`
+	OK    bool   `json:"ok"`
+	URL   string `json:"url,omitempty"`
+	Title string `json:"title,omitempty"`
+	LoadReport
 }
 
 type DomRequest struct {
@@ -100,9 +143,24 @@ type DomWaitResponse struct {
 	State string `json:"state"`
 }
 
+// ScreenshotRequest captures the viewport, unless Selector, Clip or FullPage
+// is set (one at most).
 type ScreenshotRequest struct {
-	Selector string `json:"selector,omitempty"`
-	Format   string `json:"format,omitempty"` // "png" only for now
+	Selector       string    `json:"selector,omitempty"`
+	Format         string    `json:"format,omitempty"`  // png (default), jpeg or webp
+	Quality        int       `json:"quality,omitempty"` // jpeg and webp, 0-100
+	FullPage       bool      `json:"full_page,omitempty"`
+	Clip           *ClipRect `json:"clip,omitempty"` // document coordinates in CSS pixels
+	Scale          float64   `json:"scale,omitempty"`
+	OmitBackground bool      `json:"omit_background,omitempty"`
+	Padding        float64   `json:"padding,omitempty"` // CSS pixels around Selector
+}
+
+type ClipRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 type ScreenshotResponse struct {
@@ -110,6 +168,427 @@ type ScreenshotResponse struct {
 	Base64 string `json:"base64"`
 }
 
+// ScreenshotWidthsRequest takes a full-page PNG at each viewport width (CSS
+// pixels), restoring the viewport afterwards.
+type ScreenshotWidthsRequest struct {
+	Widths         []int   `json:"widths"`
+	Scale          float64 `json:"scale,omitempty"`
+	OmitBackground bool    `json:"omit_background,omitempty"`
+}
+
+type ScreenshotWidthsResponse struct {
+	Shots []WidthScreenshot `json:"shots"`
+}
+
+type WidthScreenshot struct {
+	Width  int    `json:"width"`
+	Base64 string `json:"base64"` // PNG
+}
+
+// PDFRequest prints the page to PDF. Sizes are in inches; zero values keep
+// Chrome's defaults (US Letter, 1cm margins, scale 1).
+type PDFRequest struct {
+	PaperWidth        float64     `json:"paper_width,omitempty"`
+	PaperHeight       float64     `json:"paper_height,omitempty"`
+	Margins           *PDFMargins `json:"margins,omitempty"`
+	Landscape         bool        `json:"landscape,omitempty"`
+	Scale             float64     `json:"scale,omitempty"`
+	Background        bool        `json:"background,omitempty"`
+	HeaderTemplate    string      `json:"header_template,omitempty"`
+	FooterTemplate    string      `json:"footer_template,omitempty"`
+	PageRanges        string      `json:"page_ranges,omitempty"`
+	PreferCSSPageSize bool        `json:"prefer_css_page_size,omitempty"`
+}
+
+type PDFMargins struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
+type PDFResponse struct {
+	Base64 string `json:"base64"`
+}
+
+type TabInfo struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+	Active bool   `json:"active,omitempty"`
+}
+
+type TabsResponse struct {
+	Tabs []TabInfo `json:"tabs"`
+}
+
+type TabNewRequest struct {
+	URL string `json:"url,omitempty"`
+}
+
+type TabRequest struct {
+	ID string `json:"id"`
+}
+
+type TabResponse struct {
+	Tab TabInfo `json:"tab"`
+}
+
+type Viewport struct {
+	Device            string  `json:"device,omitempty"`
+	Width             int64   `json:"width"`
+	Height            int64   `json:"height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor"`
+	Mobile            bool    `json:"mobile,omitempty"`
+	Touch             bool    `json:"touch,omitempty"`
+	UserAgent         string  `json:"user_agent,omitempty"`
+}
+
+// Emulation describes the overrides currently applied to the controlled tabs.
+type Emulation struct {
+	Viewport    *Viewport    `json:"viewport,omitempty"`
+	Media       *Media       `json:"media,omitempty"`
+	Locale      string       `json:"locale,omitempty"`
+	Timezone    string       `json:"timezone,omitempty"`
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+	Network     *Network     `json:"network,omitempty"`
+}
+
+// Network is a throttling profile; zero throughput means unthrottled.
+type Network struct {
+	Preset       string  `json:"preset,omitempty"`
+	Offline      bool    `json:"offline,omitempty"`
+	LatencyMs    float64 `json:"latency_ms,omitempty"`
+	DownloadKbps float64 `json:"download_kbps,omitempty"`
+	UploadKbps   float64 `json:"upload_kbps,omitempty"`
+}
+
+// NetworkThrottleRequest starts from Preset (if any) and applies the explicit
+// values on top. Off removes throttling.
+type NetworkThrottleRequest struct {
+	Network
+	Off bool `json:"off,omitempty"`
+}
+
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"` // meters
+}
+
+// Media is a CSS media type/feature override. Empty fields use the browser default.
+type Media struct {
+	Type          string `json:"type,omitempty"`           // screen | print
+	ColorScheme   string `json:"color_scheme,omitempty"`   // light | dark | no-preference
+	ReducedMotion string `json:"reduced_motion,omitempty"` // reduce | no-preference
+	ForcedColors  string `json:"forced_colors,omitempty"`  // active | none
+	Contrast      string `json:"contrast,omitempty"`       // more | less | no-preference | custom
+}
+
+// EmulateMediaRequest merges the non-empty fields into the current media override.
+type EmulateMediaRequest struct {
+	Media
+}
+
+type EmulateDeviceRequest struct {
+	Device            string  `json:"device,omitempty"` // preset name; optional
+	Width             int64   `json:"width,omitempty"`
+	Height            int64   `json:"height,omitempty"`
+	DeviceScaleFactor float64 `json:"device_scale_factor,omitempty"`
+	Mobile            *bool   `json:"mobile,omitempty"`
+	Touch             *bool   `json:"touch,omitempty"`
+	UserAgent         string  `json:"user_agent,omitempty"`
+	Landscape         bool    `json:"landscape,omitempty"`
+}
+
+type EmulateLocaleRequest struct {
+	Locale string `json:"locale"`
+}
+
+type EmulateTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
+
+type EmulateGeolocationRequest struct {
+	Geolocation
+}
+
+type EmulateClearRequest struct {
+	What string `json:"what,omitempty"` // "device" | "media" | "locale" | "timezone" | "geolocation" | "network" | "" (all)
+}
+
+type EmulationResponse struct {
+	Emulation Emulation `json:"emulation"`
+}
+
+// LogEntry is a console message, uncaught exception or browser log entry.
+// Line and column are 1-based.
+type LogEntry struct {
+	Seq    int64        `json:"seq"`
+	Time   time.Time    `json:"time"`
+	Level  string       `json:"level"`  // debug | info | warning | error
+	Source string       `json:"source"` // console | exception | network | security | …
+	Type   string       `json:"type,omitempty"`
+	Text   string       `json:"text"`
+	URL    string       `json:"url,omitempty"`
+	Line   int64        `json:"line,omitempty"`
+	Column int64        `json:"column,omitempty"`
+	Stack  []StackFrame `json:"stack,omitempty"`
+	Tab    string       `json:"tab,omitempty"`
+}
+
+type StackFrame struct {
+	Function string `json:"function,omitempty"`
+	URL      string `json:"url"`
+	Line     int64  `json:"line"`
+	Column   int64  `json:"column"`
+}
+
+type LogsRequest struct {
+	After int64     `json:"after,omitempty"` // only entries with Seq > After
+	Since time.Time `json:"since,omitzero"`
+	Level string    `json:"level,omitempty"` // minimum level
+	Limit int       `json:"limit,omitempty"` // newest N entries
+}
+
+type LogsResponse struct {
+	Entries []LogEntry `json:"entries"`
+	// Last is the newest sequence number in the buffer; pass it as After to
+	// poll for new entries.
+	Last int64 `json:"last"`
+}
+
+// NetworkRequest is one request of the current navigation.
+type NetworkRequest struct {
+	ID         string    `json:"id"`
+	Tab        string    `json:"tab,omitempty"`
+	URL        string    `json:"url"`
+	Method     string    `json:"method"`
+	Type       string    `json:"type,omitempty"` // Document, Script, XHR, Fetch, …
+	Status     int64     `json:"status,omitempty"`
+	MIMEType   string    `json:"mime_type,omitempty"`
+	Size       int64     `json:"size"` // encoded bytes received
+	Started    time.Time `json:"started,omitzero"`
+	DurationMs float64   `json:"duration_ms"`
+	Initiator  string    `json:"initiator,omitempty"`
+	Failure    string    `json:"failure,omitempty"`
+	FromCache  bool      `json:"from_cache,omitempty"`
+	Finished   bool      `json:"finished"`
+}
+
+type NetworkLogRequest struct {
+	Filter string `json:"filter,omitempty"` // case-insensitive URL substring
+	Failed bool   `json:"failed,omitempty"` // only failed requests and HTTP errors
+}
+
+type NetworkLogResponse struct {
+	Requests []NetworkRequest `json:"requests"`
+}
+
+type NetworkBodyRequest struct {
+	ID string `json:"id"`
+}
+
+type NetworkBodyResponse struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	MIMEType string `json:"mime_type,omitempty"`
+	Body     []byte `json:"body"` // base64 in JSON
+}
+
+type HARStartResponse struct {
+	OK bool `json:"ok"`
+}
+
+type HARStopResponse struct {
+	Entries int             `json:"entries"`
+	HAR     json.RawMessage `json:"har"`
+}
+
+// HARReplayRequest answers matching requests from a HAR file. NotFound is
+// "fallback" (default: unmatched requests hit the network) or "abort".
+type HARReplayRequest struct {
+	HAR      json.RawMessage `json:"har,omitempty"`
+	NotFound string          `json:"not_found,omitempty"`
+	Off      bool            `json:"off,omitempty"`
+}
+
+type HARReplayResponse struct {
+	Active  bool `json:"active"`
+	Entries int  `json:"entries,omitempty"`
+}
+
+// Route is a mock answer for requests whose URL matches Pattern (* matches
+// anything; the query string is ignored unless the pattern contains '?').
+// Fail (a Network.ErrorReason) fails the request instead.
+type Route struct {
+	ID      string            `json:"id,omitempty"`
+	Pattern string            `json:"pattern"`
+	Method  string            `json:"method,omitempty"` // empty matches any method
+	Status  int64             `json:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body,omitempty"` // base64 in JSON
+	Fail    string            `json:"fail,omitempty"`
+	DelayMs int64             `json:"delay_ms,omitempty"`
+	Hits    int64             `json:"hits,omitempty"`
+}
+
+type RouteAddRequest struct {
+	Route
+}
+
+type RouteRemoveRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type RoutesResponse struct {
+	Routes []Route `json:"routes"`
+}
+
+// PerfRequest measures the current page. With Reload, each of Runs reloads
+// the page first and the response reports median and p95.
+type PerfRequest struct {
+	Reload bool `json:"reload,omitempty"`
+	Runs   int  `json:"runs,omitempty"`
+}
+
+type PerfResponse struct {
+	URL     string             `json:"url"`
+	Runs    int                `json:"runs"`
+	Values  []PerfValue        `json:"values"`
+	Metrics map[string]float64 `json:"metrics,omitempty"` // raw Performance.getMetrics of the last run
+}
+
+// PerfValue is one measurement. Over several runs, Value is the median.
+type PerfValue struct {
+	Name    string    `json:"name"`
+	Unit    string    `json:"unit"` // ms, bytes, count or score
+	Value   float64   `json:"value"`
+	P95     float64   `json:"p95,omitempty"`
+	Samples []float64 `json:"samples,omitempty"`
+}
+
+// TraceStartRequest starts a Chrome trace; empty Categories uses the
+// DevTools performance panel's set. A leading "-" excludes a category.
+type TraceStartRequest struct {
+	Categories []string `json:"categories,omitempty"`
+}
+
+type TraceStartResponse struct {
+	OK  bool   `json:"ok"`
+	Tab string `json:"tab,omitempty"`
+}
+
+type ProfileCPURequest struct {
+	DurationMs int64 `json:"duration_ms"`
+	IntervalUs int64 `json:"interval_us,omitempty"` // sampling interval; 0 keeps Chrome's default
+	Top        int   `json:"top,omitempty"`         // functions in the summary
+}
+
+type ProfileCPUResponse struct {
+	Profile json.RawMessage   `json:"profile"` // DevTools .cpuprofile
+	Summary CPUProfileSummary `json:"summary"`
+}
+
+type CPUProfileSummary struct {
+	DurationMs float64           `json:"duration_ms"`
+	Samples    int               `json:"samples"`
+	IdleMs     float64           `json:"idle_ms"`
+	Top        []ProfileFunction `json:"top"`
+}
+
+// ProfileFunction is a function's self time in a CPU profile.
+type ProfileFunction struct {
+	Function string  `json:"function"`
+	URL      string  `json:"url,omitempty"`
+	Line     int64   `json:"line,omitempty"`
+	Column   int64   `json:"column,omitempty"`
+	SelfMs   float64 `json:"self_ms"`
+	Samples  int64   `json:"samples"`
+}
+
+type CoverageStartResponse struct {
+	OK  bool   `json:"ok"`
+	Tab string `json:"tab,omitempty"`
+}
+
+// CoverageReport is JavaScript and CSS coverage per file. Sizes count source
+// characters (UTF-16 code units, like DevTools).
+type CoverageReport struct {
+	Root    string         `json:"root,omitempty"` // serve dir; file paths are relative to it
+	Total   int            `json:"total"`
+	Used    int            `json:"used"`
+	Percent float64        `json:"percent"`
+	Files   []CoverageFile `json:"files"`
+}
+
+// CoverageFile is the coverage of one resource. Inline scripts and styles
+// count towards their page, separately per Type.
+type CoverageFile struct {
+	URL       string  `json:"url"`
+	Path      string  `json:"path,omitempty"` // under Root, when served from there
+	Type      string  `json:"type"`           // js or css
+	Total     int     `json:"total"`
+	Used      int     `json:"used"`
+	Percent   float64 `json:"percent"`
+	Covered   []int   `json:"covered_lines,omitempty"`
+	Uncovered []int   `json:"uncovered_lines,omitempty"`
+}
+
+// RecordStartRequest starts a screencast; zero values keep Chrome's defaults
+// (viewport size, every frame).
+type RecordStartRequest struct {
+	Quality       int `json:"quality,omitempty"` // JPEG quality 0-100
+	MaxWidth      int `json:"max_width,omitempty"`
+	MaxHeight     int `json:"max_height,omitempty"`
+	EveryNthFrame int `json:"every_nth_frame,omitempty"`
+}
+
+type RecordStartResponse struct {
+	OK  bool   `json:"ok"`
+	Tab string `json:"tab,omitempty"`
+}
+
+// RecordStopRequest ends the screencast. Format "gif" streams an animated
+// GIF (thinned to FPS, default 10); "frames" streams a tar of the JPEG
+// frames, index.json and frames.ffconcat.
+type RecordStopRequest struct {
+	Format string  `json:"format"`
+	FPS    float64 `json:"fps,omitempty"`
+}
+
+// A11yTreeRequest asks for the accessibility tree of the page, or of the
+// element matching Selector.
+type A11yTreeRequest struct {
+	Selector        string `json:"selector,omitempty"`
+	InterestingOnly bool   `json:"interesting_only,omitempty"`
+}
+
+type A11yTreeResponse struct {
+	Nodes []A11yNode `json:"nodes"`
+}
+
+type A11yNode struct {
+	Role        string     `json:"role"`
+	Name        string     `json:"name,omitempty"`
+	Value       string     `json:"value,omitempty"`
+	Description string     `json:"description,omitempty"`
+	States      []string   `json:"states,omitempty"` // e.g. "focusable", "checked=false", "level=2"
+	Children    []A11yNode `json:"children,omitempty"`
+}
+
+type A11yAuditResponse struct {
+	Violations []A11yViolation `json:"violations"`
+}
+
+type A11yViolation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // critical, serious, moderate or minor
+	Selector string `json:"selector"`
+	Message  string `json:"message"`
+	HTML     string `json:"html,omitempty"` // the element's opening tag
+}
+
 type StopResponse struct {
 	OK bool `json:"ok"`
 }
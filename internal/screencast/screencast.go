@@ -0,0 +1,186 @@
+// Package screencast encodes recorded tab frames as an animated GIF or as a
+// frames directory (JPEGs, a JSON index and an ffmpeg concat script) packed
+// into a tar stream.
+package screencast
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"strings"
+	"time"
+)
+
+// Frame is a JPEG frame and when it appeared.
+type Frame struct {
+	JPEG []byte
+	Time time.Time
+}
+
+// Recording is a sequence of frames; the last one lasts until End.
+type Recording struct {
+	Frames []Frame
+	End    time.Time
+}
+
+// durations returns how long each frame stayed on screen.
+func (r Recording) durations() []time.Duration {
+	out := make([]time.Duration, len(r.Frames))
+	for i, f := range r.Frames {
+		next := r.End
+		if i+1 < len(r.Frames) {
+			next = r.Frames[i+1].Time
+		}
+		out[i] = max(next.Sub(f.Time), 0)
+	}
+	return out
+}
+
+// Sample thins the recording to about fps frames per second, for smaller
+// GIFs. A frame is kept if it stays on screen for 1/fps or comes 1/fps after
+// the last kept one, so the state the page settles in is never lost. fps <= 0
+// keeps all frames.
+func (r Recording) Sample(fps float64) Recording {
+	if fps <= 0 || len(r.Frames) == 0 {
+		return r
+	}
+	gap := time.Duration(float64(time.Second) / fps)
+	out := Recording{End: r.End, Frames: []Frame{r.Frames[0]}}
+	for i, d := range r.durations() {
+		if i == 0 {
+			continue
+		}
+		f := r.Frames[i]
+		if d >= gap || f.Time.Sub(out.Frames[len(out.Frames)-1].Time) >= gap {
+			out.Frames = append(out.Frames, f)
+		}
+	}
+	return out
+}
+
+// maxGIFPixels bounds the pixels of all frames of a GIF together. EncodeGIF
+// keeps every frame in memory, at a byte per pixel.
+var maxGIFPixels = 256 << 20
+
+// ErrGIFTooLarge is returned by EncodeGIF for recordings over its memory
+// limit.
+var ErrGIFTooLarge = errors.New("recording too large for a GIF")
+
+// CheckGIF returns ErrGIFTooLarge (wrapped) if EncodeGIF would refuse r for
+// its size. It only reads the JPEG headers.
+func CheckGIF(r Recording) error {
+	pixels := 0
+	for i, f := range r.Frames {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(f.JPEG))
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i+1, err)
+		}
+		pixels += cfg.Width * cfg.Height
+	}
+	if pixels > maxGIFPixels {
+		return fmt.Errorf("%w (%d frames, %d megapixels; the limit is %d)", ErrGIFTooLarge, len(r.Frames), pixels>>20, maxGIFPixels>>20)
+	}
+	return nil
+}
+
+// EncodeGIF writes the recording as a looping animated GIF.
+func EncodeGIF(w io.Writer, r Recording) error {
+	if len(r.Frames) == 0 {
+		return errors.New("no frames")
+	}
+	if err := CheckGIF(r); err != nil {
+		return err
+	}
+	anim := &gif.GIF{}
+	for i, d := range r.durations() {
+		img, err := jpeg.Decode(bytes.NewReader(r.Frames[i].JPEG))
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i+1, err)
+		}
+		b := img.Bounds()
+		p := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.Plan9)
+		draw.FloydSteinberg.Draw(p, p.Rect, img, b.Min)
+		anim.Image = append(anim.Image, p)
+		// GIF delays are in 1/100s; viewers slow down anything under 2.
+		anim.Delay = append(anim.Delay, max(int(d/(10*time.Millisecond)), 2))
+		anim.Config.Width = max(anim.Config.Width, b.Dx())
+		anim.Config.Height = max(anim.Config.Height, b.Dy())
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// Index describes a frames directory.
+type Index struct {
+	DurationMs int64        `json:"duration_ms"`
+	Frames     []IndexFrame `json:"frames"`
+}
+
+type IndexFrame struct {
+	File       string `json:"file"`
+	TimeMs     int64  `json:"time_ms"` // since the first frame
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// IndexFile and ConcatFile are the names of the index and the ffmpeg concat
+// script in a frames directory.
+const (
+	IndexFile  = "index.json"
+	ConcatFile = "frames.ffconcat"
+)
+
+// WriteFramesTar writes the frames as frame-00001.jpg, … plus IndexFile and
+// ConcatFile, as a tar stream.
+func WriteFramesTar(w io.Writer, r Recording) error {
+	if len(r.Frames) == 0 {
+		return errors.New("no frames")
+	}
+	tw := tar.NewWriter(w)
+	add := func(name string, data []byte, mod time.Time) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: mod}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	start := r.Frames[0].Time
+	var idx Index
+	var concat strings.Builder
+	concat.WriteString("ffconcat version 1.0\n")
+	durations := r.durations()
+	for i, f := range r.Frames {
+		name := fmt.Sprintf("frame-%05d.jpg", i+1)
+		if err := add(name, f.JPEG, f.Time); err != nil {
+			return err
+		}
+		idx.Frames = append(idx.Frames, IndexFrame{
+			File:       name,
+			TimeMs:     f.Time.Sub(start).Milliseconds(),
+			DurationMs: durations[i].Milliseconds(),
+		})
+		fmt.Fprintf(&concat, "file '%s'\nduration %.3f\n", name, durations[i].Seconds())
+	}
+	// The concat demuxer ignores the last duration unless the file repeats.
+	fmt.Fprintf(&concat, "file '%s'\n", idx.Frames[len(idx.Frames)-1].File)
+	idx.DurationMs = r.End.Sub(start).Milliseconds()
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := add(IndexFile, append(data, '\n'), r.End); err != nil {
+		return err
+	}
+	if err := add(ConcatFile, []byte(concat.String()), r.End); err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,148 @@
+package screencast
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFrame(t *testing.T, c color.Color, at time.Time) Frame {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := range 16 {
+		for x := range 32 {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return Frame{JPEG: buf.Bytes(), Time: at}
+}
+
+func testRecording(t *testing.T) Recording {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Recording{
+		Frames: []Frame{
+			testFrame(t, color.White, t0),
+			testFrame(t, color.Black, t0.Add(500*time.Millisecond)),
+			testFrame(t, color.White, t0.Add(520*time.Millisecond)),
+		},
+		End: t0.Add(2 * time.Second),
+	}
+}
+
+func TestEncodeGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, testRecording(t)); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 || g.Config.Width != 32 || g.Config.Height != 16 {
+		t.Fatalf("gif: %d frames, %dx%d", len(g.Image), g.Config.Width, g.Config.Height)
+	}
+	if want := []int{50, 2, 148}; !slices.Equal(g.Delay, want) {
+		t.Fatalf("delays = %v, want %v", g.Delay, want)
+	}
+}
+
+func TestEncodeGIF_TooLarge(t *testing.T) {
+	defer func(n int) { maxGIFPixels = n }(maxGIFPixels)
+	maxGIFPixels = 2 * 32 * 16
+
+	r := testRecording(t)
+	if err := CheckGIF(Recording{Frames: r.Frames[:2], End: r.End}); err != nil {
+		t.Fatalf("two frames: %v", err)
+	}
+	if err := CheckGIF(r); !errors.Is(err, ErrGIFTooLarge) {
+		t.Fatalf("CheckGIF = %v, want ErrGIFTooLarge", err)
+	}
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, r); !errors.Is(err, ErrGIFTooLarge) {
+		t.Fatalf("err = %v, want ErrGIFTooLarge", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("wrote %d bytes before failing", buf.Len())
+	}
+}
+
+func TestSample(t *testing.T) {
+	t0 := time.Now()
+	at := func(ms int) Frame { return Frame{Time: t0.Add(time.Duration(ms) * time.Millisecond)} }
+	r := Recording{
+		// A 60fps animation from 0 to 250ms, then the page settles.
+		Frames: []Frame{at(0), at(16), at(33), at(50), at(100), at(116), at(133), at(233), at(250)},
+		End:    t0.Add(time.Second),
+	}
+	var got []int
+	for _, f := range r.Sample(10).Frames {
+		got = append(got, int(f.Time.Sub(t0).Milliseconds()))
+	}
+	// 100 and 233 come 100ms after the last kept frame, 133 and 250 stay
+	// on screen for 100ms or more.
+	if want := []int{0, 100, 133, 233, 250}; !slices.Equal(got, want) {
+		t.Fatalf("kept = %v, want %v", got, want)
+	}
+	if len(r.Sample(0).Frames) != len(r.Frames) {
+		t.Fatal("fps 0 should keep every frame")
+	}
+}
+
+func TestWriteFramesTar(t *testing.T) {
+	rec := testRecording(t)
+	var buf bytes.Buffer
+	if err := WriteFramesTar(&buf, rec); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	var names []string
+	tr := tar.NewReader(&buf)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[h.Name] = data
+		names = append(names, h.Name)
+	}
+	if got := strings.Join(names, " "); got != "frame-00001.jpg frame-00002.jpg frame-00003.jpg index.json frames.ffconcat" {
+		t.Fatalf("files = %s", got)
+	}
+	if !bytes.Equal(files["frame-00002.jpg"], rec.Frames[1].JPEG) {
+		t.Fatal("frame data differs")
+	}
+
+	var idx Index
+	if err := json.Unmarshal(files[IndexFile], &idx); err != nil {
+		t.Fatal(err)
+	}
+	if idx.DurationMs != 2000 || len(idx.Frames) != 3 || idx.Frames[2] != (IndexFrame{File: "frame-00003.jpg", TimeMs: 520, DurationMs: 1480}) {
+		t.Fatalf("index = %+v", idx)
+	}
+	wantConcat := "ffconcat version 1.0\n" +
+		"file 'frame-00001.jpg'\nduration 0.500\n" +
+		"file 'frame-00002.jpg'\nduration 0.020\n" +
+		"file 'frame-00003.jpg'\nduration 1.480\n" +
+		"file 'frame-00003.jpg'\n"
+	if string(files[ConcatFile]) != wantConcat {
+		t.Fatalf("concat:\n%s", files[ConcatFile])
+	}
+}
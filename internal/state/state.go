@@ -21,6 +21,7 @@ type Session struct {
 	SocketPath    string    `json:"socket_path"`
 	Token         string    `json:"token"`
 	Headless      bool      `json:"headless"`
+	Attached      bool      `json:"attached,omitempty"`
 	BrowserPID    int       `json:"browser_pid,omitempty"`
 	DevToolsPort  int       `json:"devtools_port,omitempty"`
 	DevToolsWSURL string    `json:"devtools_ws_url,omitempty"`
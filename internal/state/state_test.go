@@ -2,6 +2,7 @@ package state
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -38,3 +39,46 @@ func TestSaveLoadRemove(t *testing.T) {
 		t.Fatalf("expected session file removed; stat err=%v", err)
 	}
 }
+
+func TestSessionDirs(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("CANVAS_STATE_DIR", base)
+
+	for _, name := range []string{"", "default"} {
+		got, err := SessionStateDir(name)
+		if err != nil || got != base {
+			t.Fatalf("SessionStateDir(%q) = %q, %v", name, got, err)
+		}
+	}
+	agentDir, err := SessionStateDir("agent-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agentDir != filepath.Join(base, "sessions", "agent-1") {
+		t.Fatalf("agent dir = %q", agentDir)
+	}
+	for _, bad := range []string{"../x", "a/b", ".hidden"} {
+		if _, err := SessionStateDir(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+
+	if err := Save(base, Session{PID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(agentDir, Session{PID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	// A session dir without a session file is not listed.
+	if err := os.MkdirAll(filepath.Join(base, "sessions", "stale"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "default" || got[1].Name != "agent-1" || got[1].StateDir != agentDir {
+		t.Fatalf("sessions = %#v", got)
+	}
+}
@@ -1,10 +1,19 @@
 package state
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
+// DefaultSession is the name of the session that lives directly in the base
+// state dir (the layout used before named sessions existed).
+const DefaultSession = "default"
+
+var sessionNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
 // DefaultStateDir returns the directory used to store runtime session info.
 // On macOS this is typically ~/Library/Application Support/canvas.
 func DefaultStateDir() (string, error) {
@@ -17,3 +26,64 @@ func DefaultStateDir() (string, error) {
 	}
 	return filepath.Join(base, "canvas"), nil
 }
+
+// SessionStateDir returns the state dir for a named session. The default
+// session ("" or "default") uses the base state dir; others get their own
+// subdirectory under <base>/sessions/<name>.
+func SessionStateDir(name string) (string, error) {
+	base, err := DefaultStateDir()
+	if err != nil {
+		return "", err
+	}
+	return sessionDir(base, name)
+}
+
+func sessionDir(base, name string) (string, error) {
+	if name == "" || name == DefaultSession {
+		return base, nil
+	}
+	if !sessionNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid session name %q (use letters, digits, '.', '_' or '-')", name)
+	}
+	return filepath.Join(base, "sessions", name), nil
+}
+
+type SessionDir struct {
+	Name     string
+	StateDir string
+}
+
+// ListSessions returns every session with a session file under the base state
+// dir, default session first, then by name.
+func ListSessions() ([]SessionDir, error) {
+	base, err := DefaultStateDir()
+	if err != nil {
+		return nil, err
+	}
+	return listSessions(base)
+}
+
+func listSessions(base string) ([]SessionDir, error) {
+	var out []SessionDir
+	if _, err := os.Stat(SessionPath(base)); err == nil {
+		out = append(out, SessionDir{Name: DefaultSession, StateDir: base})
+	}
+	entries, err := os.ReadDir(filepath.Join(base, "sessions"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return out, nil
+		}
+		return nil, err
+	}
+	// ReadDir returns entries sorted by name.
+	for _, e := range entries {
+		if !e.IsDir() || !sessionNameRe.MatchString(e.Name()) {
+			continue
+		}
+		dir := filepath.Join(base, "sessions", e.Name())
+		if _, err := os.Stat(SessionPath(dir)); err == nil {
+			out = append(out, SessionDir{Name: e.Name(), StateDir: dir})
+		}
+	}
+	return out, nil
+}
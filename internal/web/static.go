@@ -69,14 +69,43 @@ func (h *StaticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *StaticHandler) serveIndex(w http.ResponseWriter, r *http.Request, dir string) bool {
+	p, ok := indexFile(dir)
+	if ok {
+		http.ServeFile(w, r, p)
+	}
+	return ok
+}
+
+func indexFile(dir string) (string, bool) {
 	for _, name := range []string{"index.html", "index.htm"} {
 		p := filepath.Join(dir, name)
 		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
-			http.ServeFile(w, r, p)
-			return true
+			return p, true
 		}
 	}
-	return false
+	return "", false
+}
+
+// Resolve returns the file ServeHTTP serves for urlPath (a directory's
+// index.html), or false if it serves none.
+func (h *StaticHandler) Resolve(urlPath string) (string, bool) {
+	target := filepath.Clean(filepath.Join(h.rootAbs, filepath.FromSlash(path.Clean("/"+urlPath))))
+	if !withinRoot(h.rootAbs, target) {
+		return "", false
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", false
+	}
+	if info.IsDir() {
+		return indexFile(target)
+	}
+	return target, true
+}
+
+// Root is the absolute directory being served.
+func (h *StaticHandler) Root() string {
+	return h.rootAbs
 }
 
 func withinRoot(rootAbs, targetAbs string) bool {
@@ -145,3 +145,37 @@ func TestStaticHandler_PreventsTraversalOutsideRoot(t *testing.T) {
 		t.Fatalf("status=%d body=%q", resp.StatusCode, string(b))
 	}
 }
+
+func TestStaticHandler_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub", "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"app.js", filepath.Join("sub", "index.html")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h, err := NewStaticHandler(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/app.js", filepath.Join(h.Root(), "app.js")},
+		{"/sub/", filepath.Join(h.Root(), "sub", "index.html")},
+		{"/sub", filepath.Join(h.Root(), "sub", "index.html")},
+		{"/sub/empty/", ""},
+		{"/missing.css", ""},
+		{"/../etc/passwd", ""},
+	}
+	for _, tc := range cases {
+		got, ok := h.Resolve(tc.path)
+		if ok != (tc.want != "") || got != tc.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tc.path, got, ok, tc.want)
+		}
+	}
+}